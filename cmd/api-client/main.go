@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"shipping-gateway/internal/config"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"shipping-gateway/internal/usecase"
+	"slices"
+	"strings"
+)
+
+// api-client issues an API key from the command line, typically the first admin key used to manage the other clients
+func main() {
+	name := flag.String("name", "", "name of the API client")
+	scopes := flag.String("scopes", "", "comma separated scopes granted to the client: "+strings.Join(entity.Scopes, ", ")+
+		" (default admin, or every other scope for a merchant client)")
+	merchantID := flag.String("merchant", "", "ID of the merchant the client acts for, empty for the platform")
+	configFiles := config.FileFlags(flag.CommandLine)
+	flag.Parse()
+
+	// merchant clients cannot be granted admin, so they default to every scope but admin
+	if *scopes == "" {
+		*scopes = entity.ScopeAdmin
+		if *merchantID != "" {
+			*scopes = strings.Join([]string{entity.ScopeRates, entity.ScopeTracking, entity.ScopeShipments}, ",")
+		}
+	}
+
+	req := model.APIClientCreateRequest{Name: strings.TrimSpace(*name), MerchantID: *merchantID, Scopes: strings.Split(*scopes, ",")}
+	if req.Name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for i, scope := range req.Scopes {
+		req.Scopes[i] = strings.TrimSpace(scope)
+		if !slices.Contains(entity.Scopes, req.Scopes[i]) {
+			fmt.Fprintf(os.Stderr, "unknown scope %q, must be one of %s\n", req.Scopes[i], strings.Join(entity.Scopes, ", "))
+			os.Exit(2)
+		}
+	}
+
+	viperConfig := config.NewViper(configFiles())
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log)
+
+	apiClientUseCase := usecase.NewAPIClientUseCase(db, log, repository.NewAPIClientRepository(), repository.NewMerchantRepository(),
+		viperConfig.GetString("auth.key_pepper"), viperConfig.GetDuration("auth.rotation_grace"))
+	ucResp, client := apiClientUseCase.CreateClient(context.Background(), req)
+	if client == nil {
+		log.Fatalf("Failed to create API client: %s", ucResp.Message)
+	}
+
+	log.Infof("Created API client %s (%s) with scopes %s", client.ID, client.Name, strings.Join(client.Scopes, ","))
+	fmt.Println(client.APIKey)
+}
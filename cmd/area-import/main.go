@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/config"
+	"shipping-gateway/internal/repository"
+	"shipping-gateway/internal/usecase"
+	"time"
+)
+
+func main() {
+	file := flag.String("file", "", "master data CSV with one subdistrict per line to import")
+	resolve := flag.Bool("resolve", false, "map imported subdistricts to provider areas")
+	minScore := flag.Float64("min-score", 0.75, "matches scoring below this are flagged for manual review")
+	rate := flag.Int("rate", 5, "maximum provider searches per second while resolving")
+	force := flag.Bool("force", false, "resolve subdistricts again even when they already have a confident mapping")
+	batchSize := flag.Int("batch-size", 500, "number of rows saved or loaded at once")
+	configFiles := config.FileFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *file == "" && !*resolve {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	viperConfig := config.NewViper(configFiles())
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log)
+	rds := config.InitRedis(viperConfig, log)
+
+	areaImportUseCase := usecase.NewAreaImportUseCase(db, log, biteship.NewClient(viperConfig, log), rds,
+		repository.NewAreaRepository(), repository.NewProvinceRepository(), repository.NewCityRepository(),
+		repository.NewDistrictRepository(), repository.NewSubdistrictRepository())
+	ctx := context.Background()
+
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", *file, err)
+		}
+		stats, err := areaImportUseCase.ImportCSV(ctx, f, *batchSize)
+		_ = f.Close()
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", *file, err)
+		}
+		log.Infof("Imported %d provinces, %d cities, %d districts and %d subdistricts",
+			stats.Provinces, stats.Cities, stats.Districts, stats.Subdistricts)
+	}
+
+	if *resolve {
+		stats, err := areaImportUseCase.ResolveSubdistricts(ctx, usecase.AreaResolveOptions{
+			MinScore:  *minScore,
+			Interval:  time.Second / time.Duration(max(*rate, 1)),
+			Force:     *force,
+			BatchSize: *batchSize,
+		})
+		if err != nil {
+			log.Fatalf("Failed to resolve subdistricts: %v", err)
+		}
+		log.Infof("Resolved %d subdistricts, %d need review, %d failed, %d skipped",
+			stats.Resolved, stats.LowConfidence, stats.Failed, stats.Skipped)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"shipping-gateway/internal/config"
+	"shipping-gateway/internal/repository"
+	"shipping-gateway/internal/secret"
+	"shipping-gateway/internal/usecase"
+	"strings"
+)
+
+const usage = `Manage the master keys and encrypted secrets of the gateway.
+
+Usage:
+  secret keygen -id <key id>     print a new master key to prepend to ` + secret.MasterKeyEnv + `
+  secret set <name>              encrypt the value read from stdin into the secrets file, reference it as secret://<name>
+  secret rotate [-secrets-only]  re-encrypt the secrets file and merchant credentials with the current master key
+
+The -config and -env flags, given before the command, select the config used to reach the database when rotating.
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configFiles := config.FileFlags(flag.CommandLine)
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch command, args := flag.Arg(0), flag.Args()[1:]; command {
+	case "keygen":
+		keygen(args)
+	case "set":
+		set(args)
+	case "rotate":
+		rotate(args, configFiles())
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func keygen(args []string) {
+	flags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	id := flags.String("id", "", "ID of the key, e.g. the date it was created")
+	_ = flags.Parse(args)
+
+	key, err := secret.GenerateKey(*id)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Println(key)
+}
+
+func set(args []string) {
+	if len(args) != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	value = strings.TrimRight(value, "\r\n")
+	if value == "" {
+		fail(fmt.Errorf("no value read from stdin: %v", err))
+	}
+
+	cipher, store := loadStore()
+	ciphertext, err := cipher.Encrypt([]byte(value))
+	if err != nil {
+		fail(err)
+	}
+	store.Set(args[0], ciphertext)
+	if err = store.Save(); err != nil {
+		fail(err)
+	}
+	fmt.Printf("Stored %s in %s, reference it as %s%s\n", args[0], secret.SecretsFile(), secret.ReferencePrefix, args[0])
+}
+
+func rotate(args []string, configFiles config.Files) {
+	flags := flag.NewFlagSet("rotate", flag.ExitOnError)
+	secretsOnly := flags.Bool("secrets-only", false, "only re-encrypt the secrets file, without connecting to the database")
+	_ = flags.Parse(args)
+
+	cipher, store := loadStore()
+	rotated := 0
+	for _, name := range store.Names() {
+		ciphertext, _ := store.Get(name)
+		if !cipher.NeedsRotation(ciphertext) {
+			continue
+		}
+		value, err := store.Resolve(cipher, name)
+		if err != nil {
+			fail(err)
+		}
+		if ciphertext, err = cipher.Encrypt([]byte(value)); err != nil {
+			fail(err)
+		}
+		store.Set(name, ciphertext)
+		rotated++
+	}
+	if err := store.Save(); err != nil {
+		fail(err)
+	}
+	fmt.Printf("Re-encrypted %d secrets in %s\n", rotated, secret.SecretsFile())
+	if *secretsOnly {
+		return
+	}
+
+	viperConfig := config.NewViper(configFiles)
+	log := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, log)
+	merchantRepository := repository.NewMerchantRepository()
+	tenants := usecase.NewTenantRegistry(db, log, merchantRepository, cipher, nil, 0)
+	merchantUseCase := usecase.NewMerchantUseCase(db, log, merchantRepository, cipher, tenants)
+
+	count, err := merchantUseCase.RotateCredentials(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to rotate merchant credentials after %d merchants: %v", count, err)
+	}
+	log.Infof("Re-encrypted the credentials of %d merchants", count)
+}
+
+func loadStore() (*secret.Cipher, *secret.Store) {
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		fail(err)
+	}
+	if keyring.Current() == "" {
+		fail(secret.ErrNoMasterKey)
+	}
+	store, err := secret.LoadStore(secret.SecretsFile())
+	if err != nil {
+		fail(err)
+	}
+	return secret.NewCipher(keyring), store
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
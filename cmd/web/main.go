@@ -1,14 +1,18 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"shipping-gateway/internal/config"
 )
 
 func main() {
+	configFiles := config.FileFlags(flag.CommandLine)
+	flag.Parse()
+
 	gin.SetMode(gin.ReleaseMode)
-	viperConfig := config.NewViper()
+	viperConfig := config.NewViper(configFiles())
 	log := config.NewLogger(viperConfig)
 	app := config.NewGinEngine(viperConfig)
 	db := config.NewDatabase(viperConfig, log)
@@ -16,12 +20,13 @@ func main() {
 	validate := config.NewValidator(viperConfig)
 
 	config.Bootstrap(&config.BootstrapConfig{
-		DB:       db,
-		Rds:      rds,
-		App:      app,
-		Log:      log,
-		Validate: validate,
-		Config:   viperConfig,
+		DB:          db,
+		Rds:         rds,
+		App:         app,
+		Log:         log,
+		Validate:    validate,
+		Config:      viperConfig,
+		ConfigFiles: configFiles(),
 	})
 
 	webPort := viperConfig.GetString("web.port")
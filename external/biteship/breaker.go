@@ -0,0 +1,137 @@
+package biteship
+
+import (
+	"github.com/sirupsen/logrus"
+	"shipping-gateway/internal/model"
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Calls reach Biteship
+	BreakerOpen     BreakerState = "open"      // Calls fail fast until the open timeout has passed
+	BreakerHalfOpen BreakerState = "half_open" // One probe call is let through to find out whether Biteship is back
+)
+
+// CircuitBreaker stops calling Biteship after consecutive failures, so requests fail fast instead of waiting
+// for timeouts while it is down. It is shared by every client returned by WithAPIKey, as an outage affects all
+// accounts.
+type CircuitBreaker struct {
+	FailureThreshold int           // Consecutive failures opening the circuit, 0 disables the breaker
+	OpenTimeout      time.Duration // How long the circuit stays open before a probe call is let through
+	logger           *logrus.Logger
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration, logger *logrus.Logger) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		logger:           logger,
+		state:            BreakerClosed,
+	}
+}
+
+// Allow reports whether a call may be made. Once the open timeout has passed a single probe call is allowed,
+// and its result closes or reopens the circuit.
+func (b *CircuitBreaker) Allow() bool {
+	if b.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.OpenTimeout {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		b.logger.Infof("Biteship circuit half open, probing after %s", b.OpenTimeout)
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record reports the result of an allowed call. Only server errors and timeouts are failures, a rejected
+// request shows Biteship is up.
+func (b *CircuitBreaker) Record(success bool) {
+	if b.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerClosed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.FailureThreshold {
+			b.open()
+		}
+	case BreakerHalfOpen:
+		b.probing = false
+		if success {
+			b.state = BreakerClosed
+			b.failures = 0
+			b.logger.Infof("Biteship circuit closed, probe call succeeded")
+			return
+		}
+		b.failures++
+		b.open()
+	}
+}
+
+// Release gives back an allowed call that ended without a result, such as one abandoned by its caller. A released
+// probe call leaves the circuit half open, and the next call probes again.
+func (b *CircuitBreaker) Release() {
+	if b.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.logger.Warnf("Biteship circuit open after %d consecutive failures, failing fast for %s", b.failures, b.OpenTimeout)
+}
+
+// Health returns the state of the circuit
+func (b *CircuitBreaker) Health() model.ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := model.ProviderHealth{
+		Circuit:             string(b.state),
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != BreakerClosed {
+		openedAt, retryAt := b.openedAt, b.openedAt.Add(b.OpenTimeout)
+		health.OpenedAt, health.RetryAt = &openedAt, &retryAt
+	}
+	return health
+}
@@ -0,0 +1,132 @@
+package biteship
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// openBreaker returns a breaker whose circuit is open and whose open timeout has passed
+func openBreaker(t *testing.T) *CircuitBreaker {
+	t.Helper()
+	b := NewCircuitBreaker(2, time.Minute, newTestLogger())
+	b.Record(false)
+	b.Record(false)
+	if b.state != BreakerOpen || b.Allow() {
+		t.Fatalf("breaker after %d failures = %s, want open and failing fast", b.failures, b.state)
+	}
+	b.openedAt = time.Now().Add(-b.OpenTimeout)
+	return b
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	b := NewCircuitBreaker(3, time.Minute, newTestLogger())
+
+	b.Record(false)
+	b.Record(false)
+	b.Record(true)
+	if b.state != BreakerClosed || b.failures != 0 {
+		t.Fatalf("breaker after a success = %s with %d failures, want closed with none", b.state, b.failures)
+	}
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Fatalf("call %d refused by a closed circuit", i)
+		}
+		b.Record(false)
+	}
+	if b.state != BreakerOpen || b.Allow() {
+		t.Fatalf("breaker after 3 consecutive failures = %s, want open and failing fast", b.state)
+	}
+	if health := b.Health(); health.Circuit != "open" || health.ConsecutiveFailures != 3 || health.RetryAt == nil {
+		t.Errorf("Health() = %+v, want open with 3 failures and a retry time", health)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     func(b *CircuitBreaker)
+		wantState BreakerState
+		wantAllow bool // whether the next call is allowed
+	}{
+		{"successful probe closes", func(b *CircuitBreaker) { b.Record(true) }, BreakerClosed, true},
+		{"failed probe reopens", func(b *CircuitBreaker) { b.Record(false) }, BreakerOpen, false},
+		{"released probe lets the next call probe", func(b *CircuitBreaker) { b.Release() }, BreakerHalfOpen, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := openBreaker(t)
+			if !b.Allow() {
+				t.Fatalf("probe refused after the open timeout")
+			}
+			if b.state != BreakerHalfOpen || b.Allow() {
+				t.Fatalf("breaker while probing = %s, want half open refusing other calls", b.state)
+			}
+
+			tt.probe(b)
+			if b.state != tt.wantState {
+				t.Errorf("state after the probe = %s, want %s", b.state, tt.wantState)
+			}
+			if got := b.Allow(); got != tt.wantAllow {
+				t.Errorf("Allow() after the probe = %v, want %v", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := NewCircuitBreaker(0, time.Minute, newTestLogger())
+	for i := 0; i < 10; i++ {
+		b.Record(false)
+	}
+	b.Release()
+	if !b.Allow() || b.state != BreakerClosed {
+		t.Errorf("disabled breaker = %s, want every call allowed", b.state)
+	}
+}
+
+func TestClientReleasesCancelledProbe(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	config := viper.New()
+	config.Set("biteship.base_url", server.URL)
+	config.Set("biteship.retry.max_attempts", 1)
+	config.Set("biteship.breaker.failure_threshold", 2)
+	config.Set("biteship.breaker.open_timeout", time.Minute)
+	client := NewClient(config, newTestLogger())
+	client.breaker = openBreaker(t)
+
+	// the caller gives up while the probe call is in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requests
+		cancel()
+	}()
+	if _, _, err := client.GetRequest(ctx, OpTracking, "/v1/trackings", nil); err == nil {
+		t.Fatalf("GetRequest() error = nil, want the cancellation")
+	}
+
+	if state := client.breaker.Health().Circuit; state != string(BreakerHalfOpen) {
+		t.Errorf("circuit after a cancelled probe = %s, want half_open", state)
+	}
+	if !client.breaker.Allow() {
+		t.Errorf("Allow() after a cancelled probe = false, want the next call to probe")
+	}
+}
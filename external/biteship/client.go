@@ -2,19 +2,29 @@ package biteship
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
+// ErrCircuitOpen is returned without calling Biteship while the circuit breaker is open
+var ErrCircuitOpen = errors.New("biteship circuit breaker is open")
+
 type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	timeouts   *atomic.Pointer[Timeouts] // shared with the clients returned by WithAPIKey
+	retry      RetryPolicy
+	breaker    *CircuitBreaker
 	logger     *logrus.Logger
 }
 
@@ -26,87 +36,208 @@ const (
 	V1TrackingByWaybill = "/v1/trackings/%s/couriers/%s"
 )
 
+// Operation describes how a Biteship endpoint is called
+type Operation struct {
+	Name       string // Key of the endpoint timeout in biteship.timeouts
+	Idempotent bool   // The call has no side effect on Biteship, so it is retried on server errors and timeouts
+}
+
+var (
+	OpSearchAreas  = Operation{Name: "areas", Idempotent: true}
+	OpCourierRates = Operation{Name: "rates", Idempotent: true}
+	OpTracking     = Operation{Name: "tracking", Idempotent: true}
+)
+
+// Timeouts are the request timeouts, per operation name with a default for the others
+type Timeouts struct {
+	Default   time.Duration
+	Endpoints map[string]time.Duration
+}
+
+func (t Timeouts) For(op Operation) time.Duration {
+	if timeout, ok := t.Endpoints[op.Name]; ok && timeout > 0 {
+		return timeout
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return 30 * time.Second
+}
+
+func TimeoutsFromConfig(cfg *viper.Viper) Timeouts {
+	endpoints := make(map[string]time.Duration)
+	for name := range cfg.GetStringMap("biteship.timeouts") {
+		endpoints[name] = cfg.GetDuration("biteship.timeouts." + name)
+	}
+	return Timeouts{
+		Default:   cfg.GetDuration("biteship.timeout"),
+		Endpoints: endpoints,
+	}
+}
+
+// RetryPolicy retries idempotent calls with exponential backoff and full jitter, so clients retrying at the
+// same time do not hit Biteship together
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per call including the first one
+	BaseDelay   time.Duration // Upper bound of the delay before the first retry, doubled on every retry
+	MaxDelay    time.Duration // Upper bound of any delay
+}
+
+// backoff returns the delay before the given retry, starting at 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if shift := retry - 1; shift < 32 && p.BaseDelay<<shift < ceiling {
+		ceiling = p.BaseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
 func NewClient(cfg *viper.Viper, logger *logrus.Logger) *Client {
-	return &Client{
-		baseURL: cfg.GetString("biteship.base_url"),
-		apiKey:  cfg.GetString("biteship.api_key"),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Set a timeout for HTTP requests
+	c := &Client{
+		baseURL:    cfg.GetString("biteship.base_url"),
+		apiKey:     cfg.GetString("biteship.api_key"),
+		httpClient: &http.Client{},
+		timeouts:   &atomic.Pointer[Timeouts]{},
+		retry: RetryPolicy{
+			MaxAttempts: cfg.GetInt("biteship.retry.max_attempts"),
+			BaseDelay:   cfg.GetDuration("biteship.retry.base_delay"),
+			MaxDelay:    cfg.GetDuration("biteship.retry.max_delay"),
 		},
+		breaker: NewCircuitBreaker(cfg.GetInt("biteship.breaker.failure_threshold"),
+			cfg.GetDuration("biteship.breaker.open_timeout"), logger),
 		logger: logger,
 	}
+	c.SetTimeouts(TimeoutsFromConfig(cfg))
+	return c
 }
 
-func (c *Client) GetRequest(endpoint string, queryParams map[string]string) (int, []byte, error) {
-	bRes := make([]byte, 0)
+// SetTimeouts changes the timeouts of the requests made from now on, by this client and every client
+// returned by WithAPIKey
+func (c *Client) SetTimeouts(timeouts Timeouts) {
+	c.timeouts.Store(&timeouts)
+}
 
-	req, err := http.NewRequest(http.MethodGet, c.baseURL+endpoint, nil)
-	if err != nil {
-		return http.StatusInternalServerError, bRes, err
-	}
+// Breaker returns the circuit breaker shared by this client and every client returned by WithAPIKey
+func (c *Client) Breaker() *CircuitBreaker {
+	return c.breaker
+}
 
-	// Add query parameters
-	q := req.URL.Query()
-	for k, v := range queryParams {
-		q.Add(k, v)
-	}
-	req.URL.RawQuery = q.Encode()
+// WithAPIKey returns a client calling Biteship with another account. It shares the HTTP client, and so its
+// connection pool, and the circuit breaker with the original client.
+func (c *Client) WithAPIKey(apiKey string) *Client {
+	clone := *c
+	clone.apiKey = apiKey
+	return &clone
+}
 
-	// Add API key to header
-	req.Header.Set("Authorization", c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+func (c *Client) GetRequest(ctx context.Context, op Operation, endpoint string, queryParams map[string]string) (int, []byte, error) {
+	return c.send(ctx, op, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := c.httpClient.Do(req)
+		// Add query parameters
+		q := req.URL.Query()
+		for k, v := range queryParams {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+		return req, nil
+	}, queryParams)
+}
+
+func (c *Client) PostRequest(ctx context.Context, op Operation, endpoint string, body any) (int, []byte, error) {
+	reqBody, err := json.Marshal(body)
 	if err != nil {
-		c.logger.Errorf("Error making GET request to %s: %v", req.URL.String(), err)
-		return http.StatusInternalServerError, bRes, err
+		c.logger.Errorf("Error marshalling request body: %v", err)
+		return http.StatusInternalServerError, make([]byte, 0), err
 	}
-	defer resp.Body.Close()
-	bRes, _ = io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
-		// log http request response with all data including headers and body
-		c.logger.WithField("request", queryParams).
-			WithField("response", bRes).
-			WithField("headers", req.Header).
-			Errorf("Unexpected status code: %d", resp.StatusCode)
+	return c.send(ctx, op, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+endpoint, bytes.NewReader(reqBody))
+	}, string(reqBody))
+}
 
-		return resp.StatusCode, bRes, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// send makes the request through the circuit breaker, retrying idempotent operations on server errors and
+// timeouts. The request is built again for every attempt, as its body can only be read once.
+func (c *Client) send(ctx context.Context, op Operation, newRequest func(ctx context.Context) (*http.Request, error),
+	logRequest any) (int, []byte, error) {
+	attempts := 1
+	if op.Idempotent && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
 	}
 
-	return resp.StatusCode, bRes, nil
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.Allow() {
+			return http.StatusServiceUnavailable, make([]byte, 0), ErrCircuitOpen
+		}
+
+		statusCode, bRes, err := c.attempt(ctx, op, newRequest, logRequest)
+		failed := statusCode == 0 || statusCode >= http.StatusInternalServerError
+		if ctx.Err() == nil {
+			c.breaker.Record(!failed)
+		} else {
+			// a call abandoned by its caller says nothing about Biteship
+			c.breaker.Release()
+		}
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		if !failed || attempt >= attempts || ctx.Err() != nil {
+			return statusCode, bRes, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		c.logger.Warnf("Biteship %s attempt %d of %d failed: %v, retrying in %s", op.Name, attempt, attempts, err, delay)
+		select {
+		case <-ctx.Done():
+			return statusCode, bRes, err
+		case <-time.After(delay):
+		}
+	}
 }
 
-func (c *Client) PostRequest(endpoint string, body any) (int, []byte, error) {
-	reqBody, err := json.Marshal(body)
+// attempt makes a single request with the timeout of the operation. The status code is 0 when no response
+// was received.
+func (c *Client) attempt(ctx context.Context, op Operation, newRequest func(ctx context.Context) (*http.Request, error),
+	logRequest any) (int, []byte, error) {
 	bRes := make([]byte, 0)
-	if err != nil {
-		c.logger.Errorf("Error marshalling request body: %v", err)
-		return http.StatusInternalServerError, bRes, err
-	}
 
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+endpoint, bytes.NewReader(reqBody))
+	ctx, cancel := context.WithTimeout(ctx, c.timeouts.Load().For(op))
+	defer cancel()
+
+	req, err := newRequest(ctx)
 	if err != nil {
-		c.logger.Errorf("Error creating POST request: %v", err)
+		c.logger.Errorf("Error creating %s request: %v", op.Name, err)
 		return http.StatusInternalServerError, bRes, err
 	}
 
+	// Add API key to header
 	req.Header.Set("Authorization", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.Errorf("Error making POST request to %s: %v", req.URL.String(), err)
-		return http.StatusInternalServerError, bRes, err
+		c.logger.Errorf("Error making %s request to %s: %v", req.Method, req.URL.String(), err)
+		return 0, bRes, err
 	}
 	defer resp.Body.Close()
 
-	bRes, _ = io.ReadAll(resp.Body)
+	bRes, err = io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.Errorf("Error reading response of %s request to %s: %v", req.Method, req.URL.String(), err)
+		return 0, bRes, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		// log http request response with all data including headers and body
-		c.logger.WithField("request", string(reqBody)).
+		c.logger.WithField("request", logRequest).
 			WithField("response", bRes).
-			WithField("headers", req.Header).
+			WithField("headers", redactHeaders(req.Header)).
 			Errorf("Unexpected status code: %d", resp.StatusCode)
 
 		return resp.StatusCode, bRes, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
@@ -115,17 +246,38 @@ func (c *Client) PostRequest(endpoint string, body any) (int, []byte, error) {
 	return resp.StatusCode, bRes, nil
 }
 
-func (c *Client) SearchAreas(query string) (resp *AreaResponse, err *ErrorResponse) {
+// redactHeaders returns a copy of the headers that is safe to log, with the API key hidden
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	if redacted.Get("Authorization") != "" {
+		redacted.Set("Authorization", "[REDACTED]")
+	}
+	return redacted
+}
+
+// errorResponse converts a failed call to the error returned to callers. Biteship error bodies are kept,
+// an open circuit and timeouts are reported as the provider being unavailable.
+func errorResponse(bRes []byte, err error) *ErrorResponse {
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return NewErrorResponse(ErrProviderUnavailable, ErrProviderUnavailable.GetMessage())
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewErrorResponse(ErrProviderTimeout, ErrProviderTimeout.GetMessage())
+	}
+	return ErrorResponseFromBytes(bRes)
+}
+
+func (c *Client) SearchAreas(ctx context.Context, query string) (resp *AreaResponse, err *ErrorResponse) {
 	queryParams := map[string]string{
 		"countries": "ID",
 		"input":     query,
 		"type":      "single",
 	}
 
-	_, bRes, errResp := c.GetRequest(V1GetArea, queryParams)
+	_, bRes, errResp := c.GetRequest(ctx, OpSearchAreas, V1GetArea, queryParams)
 	if errResp != nil {
-		c.logger.Errorf("Error getting area: %v, biteship resp : %s", err, bRes)
-		return nil, ErrorResponseFromBytes(bRes)
+		c.logger.Errorf("Error getting area: %v, biteship resp : %s", errResp, bRes)
+		return nil, errorResponse(bRes, errResp)
 	}
 
 	if err := json.Unmarshal(bRes, &resp); err != nil {
@@ -136,14 +288,14 @@ func (c *Client) SearchAreas(query string) (resp *AreaResponse, err *ErrorRespon
 	return resp, nil
 }
 
-func (c *Client) GetCourierRates(request RateRequest) (resp *RateResponse, errResp *ErrorResponse) {
+func (c *Client) GetCourierRates(ctx context.Context, request RateRequest) (resp *RateResponse, errResp *ErrorResponse) {
 	// convert request to json string for logging
 	requestJSON, _ := json.Marshal(request)
 	c.logger.Debugf("Requesting courier rates with request: %s", requestJSON)
-	_, bRes, err := c.PostRequest(V1GetCourierRates, request)
+	_, bRes, err := c.PostRequest(ctx, OpCourierRates, V1GetCourierRates, request)
 	if err != nil {
 		c.logger.Errorf("Error getting courier rates: %v, biteship", err)
-		return nil, ErrorResponseFromBytes(bRes)
+		return nil, errorResponse(bRes, err)
 	}
 
 	if err := json.Unmarshal(bRes, &resp); err != nil {
@@ -154,18 +306,18 @@ func (c *Client) GetCourierRates(request RateRequest) (resp *RateResponse, errRe
 	return resp, nil
 }
 
-func (c *Client) GetTrackingByWaybill(waybill, courier string) (resp *TrackingResponse, errResp *ErrorResponse) {
+func (c *Client) GetTrackingByWaybill(ctx context.Context, waybill, courier string) (resp *TrackingResponse, errResp *ErrorResponse) {
 	c.logger.Debugf("Requesting tracking by waybill: %s, courier: %s", waybill, courier)
 	endpoint := fmt.Sprintf(V1TrackingByWaybill, waybill, courier)
-	statusCode, bRes, err := c.GetRequest(endpoint, nil)
+	statusCode, bRes, err := c.GetRequest(ctx, OpTracking, endpoint, nil)
 	if err != nil {
 		if statusCode != http.StatusInternalServerError {
-			errResp := ErrorResponseFromBytes(bRes)
+			errResp := errorResponse(bRes, err)
 			c.logger.Errorf("failed getting tracking by waybill: %v, biteship response: %s", err, string(bRes))
 			return nil, errResp
 		}
 		c.logger.Errorf("Error getting tracking by waybill: %v, biteship", err)
-		return nil, ErrorResponseFromBytes(bRes)
+		return nil, errorResponse(bRes, err)
 	}
 
 	if err := json.Unmarshal(bRes, &resp); err != nil {
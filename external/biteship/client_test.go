@@ -0,0 +1,111 @@
+package biteship
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "biteship_live_key")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(header)
+	if got := redacted.Get("Authorization"); got != "[REDACTED]" {
+		t.Errorf("Authorization = %q, want it redacted", got)
+	}
+	if got := redacted.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want it kept", got)
+	}
+	if got := header.Get("Authorization"); got != "biteship_live_key" {
+		t.Errorf("Authorization of the request = %q, want it unchanged", got)
+	}
+	if redactHeaders(http.Header{}).Get("Authorization") != "" {
+		t.Errorf("redactHeaders() added an Authorization header")
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	// opCreateOrder stands for a call with side effects on Biteship, which must never be sent twice
+	opCreateOrder := Operation{Name: "orders"}
+
+	tests := []struct {
+		name         string
+		op           Operation
+		statuses     []int // status of each attempt, the last one repeating
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"server error retried up to max attempts", OpCourierRates, []int{http.StatusBadGateway}, 3, http.StatusBadGateway},
+		{"server error then success", OpCourierRates, []int{http.StatusServiceUnavailable, http.StatusOK}, 2, http.StatusOK},
+		{"client error not retried", OpCourierRates, []int{http.StatusBadRequest}, 1, http.StatusBadRequest},
+		{"non idempotent call not retried", opCreateOrder, []int{http.StatusInternalServerError}, 1, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			bodies := make([]string, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(body))
+				status := tt.statuses[min(len(bodies), len(tt.statuses))-1]
+				mu.Unlock()
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			config := viper.New()
+			config.Set("biteship.base_url", server.URL)
+			config.Set("biteship.retry.max_attempts", 3)
+			config.Set("biteship.retry.base_delay", time.Millisecond)
+			config.Set("biteship.retry.max_delay", time.Millisecond)
+			client := NewClient(config, newTestLogger())
+
+			status, _, _ := client.PostRequest(context.Background(), tt.op, V1GetCourierRates, map[string]string{"origin_area_id": "IDNP6"})
+			if status != tt.wantStatus {
+				t.Errorf("PostRequest() status = %d, want %d", status, tt.wantStatus)
+			}
+			if len(bodies) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(bodies), tt.wantAttempts)
+			}
+			// the body is read once per attempt, so every retry must send it again
+			for i, body := range bodies {
+				if body != `{"origin_area_id":"IDNP6"}` {
+					t.Errorf("body of attempt %d = %q, want the full request body", i+1, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		retry   int
+		ceiling time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{40, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if delay := policy.backoff(tt.retry); delay <= 0 || delay > tt.ceiling {
+				t.Fatalf("backoff(%d) = %s, want within (0, %s]", tt.retry, delay, tt.ceiling)
+			}
+		}
+	}
+
+	if delay := (RetryPolicy{}).backoff(1); delay != 0 {
+		t.Errorf("backoff() without delays = %s, want 0", delay)
+	}
+}
@@ -4,6 +4,8 @@ type BiteshipErrCode int
 
 const (
 	ErrInvalidParsingResponse BiteshipErrCode = 50009001
+	ErrProviderUnavailable    BiteshipErrCode = 50309001
+	ErrProviderTimeout        BiteshipErrCode = 50409001
 )
 
 const (
@@ -19,6 +21,8 @@ const (
 // ErrorMessages Map of error codes to response messages ErrRateNoCourierAvailable map to "No courier available for the given postal code or area"
 var ErrorMessages = map[BiteshipErrCode]string{
 	ErrInvalidParsingResponse: "Failed to parse response from Provider",
+	ErrProviderUnavailable:    "Provider is temporarily unavailable, please retry later",
+	ErrProviderTimeout:        "Provider did not respond in time",
 	ErrInvalidAuthentication:  "Invalid third party authentication credentials",
 	ErrRateInvalidPostalCode:  "Invalid postal code provided",
 	ErrRateInvalidParameter:   "Invalid parameter provided",
@@ -29,6 +33,8 @@ var ErrorMessages = map[BiteshipErrCode]string{
 // ErrorCodeToHTTPStatus Map ErrorCode to API Response HTTP Code
 var ErrorCodeToHTTPStatus = map[BiteshipErrCode]int{
 	ErrInvalidParsingResponse: 500, // Internal Server Error
+	ErrProviderUnavailable:    503, // Service Unavailable
+	ErrProviderTimeout:        504, // Gateway Timeout
 	ErrInvalidAuthentication:  401, // Unauthorized
 	ErrRateInvalidPostalCode:  400, // Bad Request
 	ErrRateInvalidParameter:   400, // Bad Request
@@ -102,10 +102,48 @@ type Pricing struct {
 	ShipmentDurationRange        string        `json:"shipment_duration_range"`
 	ShipmentDurationUnit         string        `json:"shipment_duration_unit"`
 	Price                        int           `json:"price"`
+	InsuranceFee                 int           `json:"insurance_fee,omitempty"`
+	CashOnDeliveryFee            int           `json:"cash_on_delivery_fee,omitempty"`
 	TaxLines                     []interface{} `json:"tax_lines,omitempty"`
 	Type                         string        `json:"type"`
 }
 
+// ToPriceFees converts the tax lines and any provider insurance or COD fee to itemized fees.
+// Tax lines are loosely typed by the provider, so only entries carrying a numeric amount are kept.
+func (p Pricing) ToPriceFees() []model.PriceFee {
+	fees := make([]model.PriceFee, 0, len(p.TaxLines))
+	for _, line := range p.TaxLines {
+		taxLine, ok := line.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		amount, ok := taxLine["amount"].(float64)
+		if !ok {
+			continue
+		}
+
+		name, _ := taxLine["name"].(string)
+		if name == "" {
+			name, _ = taxLine["type"].(string)
+		}
+
+		fees = append(fees, model.PriceFee{
+			Type:   model.PriceFeeTypeTax,
+			Name:   name,
+			Amount: int(amount),
+		})
+	}
+
+	if p.InsuranceFee > 0 {
+		fees = append(fees, model.PriceFee{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: p.InsuranceFee})
+	}
+	if p.CashOnDeliveryFee > 0 {
+		fees = append(fees, model.PriceFee{Type: model.PriceFeeTypeCOD, Name: "cash_on_delivery", Amount: p.CashOnDeliveryFee})
+	}
+	return fees
+}
+
 type RateResponse struct {
 	Success     bool               `json:"success"`
 	Message     string             `json:"message"`
@@ -135,7 +173,16 @@ func (r RateResponse) ToCourierRateResponse() model.CourierRateResponse {
 			ServiceName: pricing.CourierServiceName,
 			ServiceCode: pricing.CourierServiceCode,
 			Price:       pricing.Price,
+			Currency:    pricing.Currency,
+			Fees:        pricing.ToPriceFees(),
 			ETD:         pricing.Duration,
+			Capabilities: model.CourierCapabilities{
+				CashOnDelivery:    pricing.AvailableForCashOnDelivery,
+				Insurance:         pricing.AvailableForInsurance,
+				InstantWaybill:    pricing.AvailableForInstantWaybillID,
+				ProofOfDelivery:   pricing.AvailableForProofOfDelivery,
+				CollectionMethods: pricing.AvailableCollectionMethod,
+			},
 		}
 		prices = append(prices, price)
 	}
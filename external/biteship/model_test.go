@@ -0,0 +1,83 @@
+package biteship
+
+import (
+	"encoding/json"
+	"reflect"
+	"shipping-gateway/internal/model"
+	"testing"
+)
+
+func TestPricingToPriceFees(t *testing.T) {
+	tests := []struct {
+		name    string
+		pricing string
+		want    []model.PriceFee
+	}{
+		{
+			name:    "tax line named",
+			pricing: `{"tax_lines":[{"name":"VAT","amount":1100}]}`,
+			want:    []model.PriceFee{{Type: model.PriceFeeTypeTax, Name: "VAT", Amount: 1100}},
+		},
+		{
+			name:    "tax line typed",
+			pricing: `{"tax_lines":[{"type":"ppn","amount":500}]}`,
+			want:    []model.PriceFee{{Type: model.PriceFeeTypeTax, Name: "ppn", Amount: 500}},
+		},
+		{
+			name:    "malformed tax lines skipped",
+			pricing: `{"tax_lines":["VAT",{"name":"VAT"},{"name":"VAT","amount":"1100"}]}`,
+			want:    []model.PriceFee{},
+		},
+		{
+			name:    "insurance and cod fees",
+			pricing: `{"insurance_fee":2500,"cash_on_delivery_fee":4000}`,
+			want: []model.PriceFee{
+				{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: 2500},
+				{Type: model.PriceFeeTypeCOD, Name: "cash_on_delivery", Amount: 4000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pricing Pricing
+			if err := json.Unmarshal([]byte(tt.pricing), &pricing); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got := pricing.ToPriceFees(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToPriceFees() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateResponseCapabilities(t *testing.T) {
+	body := `{"pricing":[{
+		"courier_code":"jne","type":"reg","price":9000,"currency":"IDR","duration":"1 - 2 days",
+		"available_for_cash_on_delivery":true,"available_for_insurance":true,
+		"available_for_instant_waybill_id":false,"available_for_proof_of_delivery":true,
+		"available_collection_method":["pickup","drop_off"],
+		"tax_lines":[{"name":"VAT","amount":990}]
+	}]}`
+
+	var rate RateResponse
+	if err := json.Unmarshal([]byte(body), &rate); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	price := rate.ToCourierRateResponse().Data.Prices[0]
+
+	wantCapabilities := model.CourierCapabilities{
+		CashOnDelivery:    true,
+		Insurance:         true,
+		ProofOfDelivery:   true,
+		CollectionMethods: []string{"pickup", "drop_off"},
+	}
+	if !reflect.DeepEqual(price.Capabilities, wantCapabilities) {
+		t.Errorf("Capabilities = %+v, want %+v", price.Capabilities, wantCapabilities)
+	}
+	if price.Currency != "IDR" || price.Price != 9000 || price.ServiceType != "reg" {
+		t.Errorf("price = %+v, want the provider currency, price and type", price)
+	}
+	if len(price.Fees) != 1 || price.Fees[0].Amount != 990 {
+		t.Errorf("Fees = %+v, want the tax line", price.Fees)
+	}
+}
@@ -0,0 +1,94 @@
+package address
+
+// abbreviations maps the short forms found in Indonesian addresses to their full words.
+// Keys are lowercase and without the trailing dot.
+var abbreviations = map[string]string{
+	"jl":       "Jalan",
+	"jln":      "Jalan",
+	"gg":       "Gang",
+	"kp":       "Kampung",
+	"kmp":      "Kampung",
+	"komp":     "Komplek",
+	"perum":    "Perumahan",
+	"blk":      "Blok",
+	"no":       "No.",
+	"kel":      "Kelurahan",
+	"kelur":    "Kelurahan",
+	"ds":       "Desa",
+	"kec":      "Kecamatan",
+	"kab":      "Kabupaten",
+	"kodya":    "Kota",
+	"prov":     "Provinsi",
+	"propinsi": "Provinsi",
+}
+
+// provinces are the normalized names of the Indonesian provinces, including the forms commonly used in addresses
+var provinces = map[string]string{
+	"aceh":                       "Aceh",
+	"nanggroe aceh darussalam":   "Aceh",
+	"sumatera utara":             "Sumatera Utara",
+	"sumatra utara":              "Sumatera Utara",
+	"sumut":                      "Sumatera Utara",
+	"sumatera barat":             "Sumatera Barat",
+	"sumatra barat":              "Sumatera Barat",
+	"sumbar":                     "Sumatera Barat",
+	"riau":                       "Riau",
+	"kepulauan riau":             "Kepulauan Riau",
+	"kepri":                      "Kepulauan Riau",
+	"jambi":                      "Jambi",
+	"sumatera selatan":           "Sumatera Selatan",
+	"sumatra selatan":            "Sumatera Selatan",
+	"sumsel":                     "Sumatera Selatan",
+	"bangka belitung":            "Kepulauan Bangka Belitung",
+	"kepulauan bangka belitung":  "Kepulauan Bangka Belitung",
+	"babel":                      "Kepulauan Bangka Belitung",
+	"bengkulu":                   "Bengkulu",
+	"lampung":                    "Lampung",
+	"dki jakarta":                "DKI Jakarta",
+	"jakarta":                    "DKI Jakarta",
+	"dki":                        "DKI Jakarta",
+	"banten":                     "Banten",
+	"jawa barat":                 "Jawa Barat",
+	"jabar":                      "Jawa Barat",
+	"jawa tengah":                "Jawa Tengah",
+	"jateng":                     "Jawa Tengah",
+	"di yogyakarta":              "DI Yogyakarta",
+	"daerah istimewa yogyakarta": "DI Yogyakarta",
+	"diy":                        "DI Yogyakarta",
+	"jawa timur":                 "Jawa Timur",
+	"jatim":                      "Jawa Timur",
+	"bali":                       "Bali",
+	"nusa tenggara barat":        "Nusa Tenggara Barat",
+	"ntb":                        "Nusa Tenggara Barat",
+	"nusa tenggara timur":        "Nusa Tenggara Timur",
+	"ntt":                        "Nusa Tenggara Timur",
+	"kalimantan barat":           "Kalimantan Barat",
+	"kalbar":                     "Kalimantan Barat",
+	"kalimantan tengah":          "Kalimantan Tengah",
+	"kalteng":                    "Kalimantan Tengah",
+	"kalimantan selatan":         "Kalimantan Selatan",
+	"kalsel":                     "Kalimantan Selatan",
+	"kalimantan timur":           "Kalimantan Timur",
+	"kaltim":                     "Kalimantan Timur",
+	"kalimantan utara":           "Kalimantan Utara",
+	"kaltara":                    "Kalimantan Utara",
+	"sulawesi utara":             "Sulawesi Utara",
+	"sulut":                      "Sulawesi Utara",
+	"gorontalo":                  "Gorontalo",
+	"sulawesi tengah":            "Sulawesi Tengah",
+	"sulteng":                    "Sulawesi Tengah",
+	"sulawesi barat":             "Sulawesi Barat",
+	"sulbar":                     "Sulawesi Barat",
+	"sulawesi selatan":           "Sulawesi Selatan",
+	"sulsel":                     "Sulawesi Selatan",
+	"sulawesi tenggara":          "Sulawesi Tenggara",
+	"sultra":                     "Sulawesi Tenggara",
+	"maluku":                     "Maluku",
+	"maluku utara":               "Maluku Utara",
+	"papua":                      "Papua",
+	"papua barat":                "Papua Barat",
+	"papua barat daya":           "Papua Barat Daya",
+	"papua tengah":               "Papua Tengah",
+	"papua pegunungan":           "Papua Pegunungan",
+	"papua selatan":              "Papua Selatan",
+}
@@ -0,0 +1,178 @@
+// Package address parses free-text Indonesian addresses into their administrative parts.
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Address struct {
+	Street      string // Street, building and house number
+	RT          string // Rukun tetangga number without leading zeros
+	RW          string // Rukun warga number without leading zeros
+	Subdistrict string // Kelurahan or desa
+	District    string // Kecamatan
+	City        string // Name of the kota or kabupaten without its type
+	CityType    string // Kota or Kabupaten, empty when the address does not say
+	Province    string // Province, using its canonical name when known
+	PostalCode  string // Five-digit postal code
+}
+
+var (
+	// abbreviationGlued separates abbreviations written without a space, as in "Jl.Sudirman"
+	abbreviationGlued = regexp.MustCompile(`(?i)\b(jl|jln|gg|kp|komp|perum|blk|no|kel|ds|kec|kab|prov)\.([^\s.])`)
+	postalCodePattern = regexp.MustCompile(`\b\d{5}\b`)
+	rtRwPattern       = regexp.MustCompile(`(?i)\bRT\.?\s*:?\s*0*(\d{1,3})\s*(?:/|\s|,)\s*(?:RW\.?\s*:?\s*)?0*(\d{1,3})\b`)
+	rtPattern         = regexp.MustCompile(`(?i)\bRT\.?\s*:?\s*0*(\d{1,3})\b`)
+	rwPattern         = regexp.MustCompile(`(?i)\bRW\.?\s*:?\s*0*(\d{1,3})\b`)
+	// labelPattern finds explicit administrative labels in the middle of a segment, as in "Kelurahan Senayan Kecamatan Kebayoran Baru"
+	labelPattern = regexp.MustCompile(`\s+(Kelurahan|Kecamatan|Kabupaten|Provinsi)\s`)
+	spaces       = regexp.MustCompile(`\s+`)
+	commas       = regexp.MustCompile(`\s*(,\s*)+`)
+)
+
+// streetPrefixes start the street part of an address
+var streetPrefixes = []string{"jalan ", "gang ", "komplek ", "perumahan ", "kampung ", "blok ", "ruko ", "apartemen ", "gedung "}
+
+// Normalize expands the abbreviations of an address, puts each part on its own comma separated segment and
+// collapses whitespace
+func Normalize(raw string) string {
+	text := strings.NewReplacer("\r\n", ", ", "\n", ", ", ";", ",", "|", ",").Replace(raw)
+	text = abbreviationGlued.ReplaceAllString(text, "$1. $2")
+
+	words := strings.Fields(text)
+	for i, word := range words {
+		trailing := ""
+		if strings.HasSuffix(word, ",") {
+			word, trailing = strings.TrimSuffix(word, ","), ","
+		}
+		if full, ok := abbreviations[strings.ToLower(strings.TrimSuffix(word, "."))]; ok {
+			word = full
+		}
+		words[i] = word + trailing
+	}
+	text = strings.Join(words, " ")
+
+	text = labelPattern.ReplaceAllString(text, ", $1 ")
+	text = commas.ReplaceAllString(spaces.ReplaceAllString(text, " "), ", ")
+	return strings.Trim(text, " ,")
+}
+
+// Parse splits a free-text address into its parts. Parts named with a label ("Kel.", "Kecamatan", "Kab.") are
+// taken as is, unlabelled parts are read as kelurahan, kecamatan and city in that order, ending right before
+// the province.
+func Parse(raw string) Address {
+	var addr Address
+	text := Normalize(raw)
+
+	if matches := postalCodePattern.FindAllStringIndex(text, -1); len(matches) > 0 {
+		last := matches[len(matches)-1]
+		addr.PostalCode = text[last[0]:last[1]]
+		text = text[:last[0]] + text[last[1]:]
+	}
+
+	if m := rtRwPattern.FindStringSubmatchIndex(text); m != nil {
+		addr.RT, addr.RW = text[m[2]:m[3]], text[m[4]:m[5]]
+		text = text[:m[0]] + text[m[1]:]
+	} else {
+		if m := rtPattern.FindStringSubmatchIndex(text); m != nil {
+			addr.RT = text[m[2]:m[3]]
+			text = text[:m[0]] + text[m[1]:]
+		}
+		if m := rwPattern.FindStringSubmatchIndex(text); m != nil {
+			addr.RW = text[m[2]:m[3]]
+			text = text[:m[0]] + text[m[1]:]
+		}
+	}
+
+	streets := make([]string, 0)
+	unlabelled := make([]string, 0)
+	for i, segment := range strings.Split(text, ",") {
+		segment = strings.Trim(spaces.ReplaceAllString(segment, " "), " .-")
+		if segment == "" {
+			continue
+		}
+		lower := strings.ToLower(segment)
+
+		switch {
+		case hasPrefix(lower, "kelurahan ", "desa "):
+			addr.Subdistrict = afterFirstWord(segment)
+		case hasPrefix(lower, "kecamatan "):
+			addr.District = afterFirstWord(segment)
+		case hasPrefix(lower, "kabupaten "):
+			addr.City, addr.CityType = afterFirstWord(segment), "Kabupaten"
+		case hasPrefix(lower, "kota ") && addr.City == "":
+			addr.City, addr.CityType = afterFirstWord(segment), "Kota"
+		case hasPrefix(lower, "provinsi "):
+			addr.Province = canonicalProvince(afterFirstWord(segment))
+		case provinces[lower] != "":
+			addr.Province = provinces[lower]
+		case hasPrefix(lower, streetPrefixes...) || strings.Contains(lower, "no. ") || (i == 0 && len(streets) == 0):
+			streets = append(streets, segment)
+		default:
+			unlabelled = append(unlabelled, segment)
+		}
+	}
+
+	// unlabelled parts fill the empty slots ending with the city, extra leading parts belong to the street
+	slots := make([]*string, 0, 3)
+	for _, slot := range []*string{&addr.Subdistrict, &addr.District, &addr.City} {
+		if *slot == "" {
+			slots = append(slots, slot)
+		}
+	}
+	if extra := len(unlabelled) - len(slots); extra > 0 {
+		streets = append(streets, unlabelled[:extra]...)
+		unlabelled = unlabelled[extra:]
+	}
+	slots = slots[len(slots)-len(unlabelled):]
+	for i, segment := range unlabelled {
+		*slots[i] = segment
+	}
+
+	addr.Street = strings.Join(streets, ", ")
+	return addr
+}
+
+// Query returns the text to search the area of the address with, naming the most specific known level and
+// its city
+func (a Address) Query() string {
+	parts := make([]string, 0, 2)
+	switch {
+	case a.District != "":
+		parts = append(parts, a.District)
+	case a.Subdistrict != "":
+		parts = append(parts, a.Subdistrict)
+	}
+
+	switch {
+	case a.City != "":
+		parts = append(parts, a.City)
+	case a.Province != "":
+		parts = append(parts, a.Province)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func canonicalProvince(name string) string {
+	if canonical, ok := provinces[strings.ToLower(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+func hasPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func afterFirstWord(s string) string {
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return strings.TrimSpace(s[i+1:])
+	}
+	return ""
+}
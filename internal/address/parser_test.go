@@ -0,0 +1,81 @@
+package address
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Address
+	}{
+		{
+			name: "labelled parts",
+			raw:  "Jl. Sudirman No. 5, RT 01/RW 02, Kel. Senayan, Kec. Kebayoran Baru, Kota Jakarta Selatan, DKI Jakarta 12190",
+			want: Address{Street: "Jalan Sudirman No. 5", RT: "1", RW: "2", Subdistrict: "Senayan", District: "Kebayoran Baru",
+				City: "Jakarta Selatan", CityType: "Kota", Province: "DKI Jakarta", PostalCode: "12190"},
+		},
+		{
+			name: "unlabelled parts end with the city",
+			raw:  "Jalan Dago No. 10, Lebakgede, Coblong, Bandung, Jawa Barat 40132",
+			want: Address{Street: "Jalan Dago No. 10", Subdistrict: "Lebakgede", District: "Coblong", City: "Bandung",
+				Province: "Jawa Barat", PostalCode: "40132"},
+		},
+		{
+			name: "glued abbreviations and kabupaten",
+			raw:  "Jl.Raya Bogor, Kec.Cibinong, Kab.Bogor, Prov. Jabar",
+			want: Address{Street: "Jalan Raya Bogor", District: "Cibinong", City: "Bogor", CityType: "Kabupaten", Province: "Jawa Barat"},
+		},
+		{
+			name: "labels in one segment",
+			raw:  "Gg. Mawar 3 Kelurahan Senayan Kecamatan Kebayoran Baru Jakarta",
+			want: Address{Street: "Gang Mawar 3", Subdistrict: "Senayan", District: "Kebayoran Baru Jakarta"},
+		},
+		{
+			name: "separate RT and RW with leading zeros",
+			raw:  "Perum Griya Asri Blok C2, RT.003 RW.010, Desa Sukamaju, Cianjur",
+			want: Address{Street: "Perumahan Griya Asri Blok C2", RT: "3", RW: "10", Subdistrict: "Sukamaju", City: "Cianjur"},
+		},
+		{
+			name: "extra unlabelled parts belong to the street",
+			raw:  "Ruko Mega Mall, Lantai 2, Gondangdia, Menteng, Jakarta Pusat\n10310",
+			want: Address{Street: "Ruko Mega Mall, Lantai 2", Subdistrict: "Gondangdia", District: "Menteng", City: "Jakarta Pusat", PostalCode: "10310"},
+		},
+		{
+			name: "last postal code wins",
+			raw:  "Jalan 17000 Pulau No. 1, Denpasar, Bali 80361",
+			want: Address{Street: "Jalan 17000 Pulau No. 1", City: "Denpasar", Province: "Bali", PostalCode: "80361"},
+		},
+		{
+			name: "empty",
+			raw:  " , ",
+			want: Address{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.raw); got != tt.want {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		addr Address
+		want string
+	}{
+		{"district and city", Address{Subdistrict: "Senayan", District: "Kebayoran Baru", City: "Jakarta Selatan"}, "Kebayoran Baru, Jakarta Selatan"},
+		{"subdistrict and province", Address{Subdistrict: "Sukamaju", Province: "Jawa Barat"}, "Sukamaju, Jawa Barat"},
+		{"city only", Address{City: "Bandung", Province: "Jawa Barat"}, "Bandung"},
+		{"nothing known", Address{Street: "Jalan Dago"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.addr.Query(); got != tt.want {
+				t.Errorf("Query() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/redis/go-redis/v9"
@@ -16,44 +17,114 @@ import (
 )
 
 type BootstrapConfig struct {
-	DB       *gorm.DB
-	Rds      *redis.Client
-	App      *gin.Engine
-	Log      *logrus.Logger
-	Validate *validator.Validate
-	Config   *viper.Viper
+	DB          *gorm.DB
+	Rds         *redis.Client
+	App         *gin.Engine
+	Log         *logrus.Logger
+	Validate    *validator.Validate
+	Config      *viper.Viper
+	ConfigFiles Files // Files the config was loaded from, watched to reload it
 }
 
 func Bootstrap(config *BootstrapConfig) {
 	// External dependencies
 	biteshipClient := biteship.NewClient(config.Config, config.Log)
+	settings := usecase.NewSettings(NewRuntimeSettings(config.Config))
 
 	// setup repositories
 	areaRepository := repository.NewAreaRepository()
 	trackingLogRepository := repository.NewTrackingLogRepository()
+	pricingRuleRepository := repository.NewPricingRuleRepository()
+	courierServiceRepository := repository.NewCourierServiceRepository()
+	quoteRepository := repository.NewQuoteRepository()
+	bulkRateJobRepository := repository.NewBulkRateJobRepository()
+	boxRepository := repository.NewBoxRepository()
+	courierRepository := repository.NewCourierRepository()
+	areaAuditRepository := repository.NewAreaAuditRepository()
+	provinceRepository := repository.NewProvinceRepository()
+	cityRepository := repository.NewCityRepository()
+	districtRepository := repository.NewDistrictRepository()
+	subdistrictRepository := repository.NewSubdistrictRepository()
+	apiClientRepository := repository.NewAPIClientRepository()
+	merchantRepository := repository.NewMerchantRepository()
 
 	//trackingLogRepository := repository.NewTrackingLogRepository()
 
 	// setup use cases
-	areaUseCase := usecase.NewAreaUseCase(biteshipClient, config.DB, config.Rds, areaRepository, config.Log)
-	shippingUseCase := usecase.NewShippingUseCase(config.DB, config.Log, config.Validate, areaUseCase, biteshipClient, config.Rds)
-	trackingUseCase := usecase.NewTrackingUseCase(config.DB, config.Log, biteshipClient, config.Rds, trackingLogRepository)
+	tenantRegistry := usecase.NewTenantRegistry(config.DB, config.Log, merchantRepository, NewSecretCipher(config.Log),
+		biteshipClient, config.Config.GetDuration("tenant.cache_ttl"))
+	courierRegistry := usecase.NewCourierRegistry(config.DB, config.Log, courierRepository, trackingLogRepository,
+		config.Config.GetDuration("courier.refresh_interval"))
+	areaSearchIndex := usecase.NewAreaSearchIndex(config.DB, config.Log, areaRepository, subdistrictRepository,
+		NewAreaSearchConfig(config.Config), settings)
+	areaUseCase := usecase.NewAreaUseCase(biteshipClient, config.DB, config.Rds, areaRepository, subdistrictRepository, config.Log,
+		areaSearchIndex, config.Config.GetFloat64("area.min_match_score"))
+	addressUseCase := usecase.NewAddressUseCase(config.DB, config.Log, subdistrictRepository)
+	pricingRuleUseCase := usecase.NewPricingRuleUseCase(config.DB, config.Log, pricingRuleRepository)
+	quoteUseCase := usecase.NewQuoteUseCase(config.DB, config.Log, quoteRepository,
+		config.Config.GetDuration("quote.ttl"), config.Config.GetString("quote.signing_key"))
+	packingUseCase := usecase.NewPackingUseCase(config.DB, config.Log, boxRepository, config.Config.GetFloat64("packing.fill_factor"))
+	shippingUseCase := usecase.NewShippingUseCase(config.DB, config.Log, config.Validate, areaUseCase, addressUseCase, courierRegistry, pricingRuleUseCase, quoteUseCase,
+		packingUseCase, courierServiceRepository, tenantRegistry, config.Rds,
+		NewShippingConfig(config.Config), settings)
+	bulkRateUseCase := usecase.NewBulkRateUseCase(config.DB, config.Log, shippingUseCase, bulkRateJobRepository,
+		NewBulkRateConfig(config.Config))
+	areaImportUseCase := usecase.NewAreaImportUseCase(config.DB, config.Log, biteshipClient, config.Rds, areaRepository,
+		provinceRepository, cityRepository, districtRepository, subdistrictRepository)
+	areaAdminUseCase := usecase.NewAreaAdminUseCase(config.DB, config.Log, config.Rds, areaRepository, areaAuditRepository,
+		subdistrictRepository, areaImportUseCase, config.Config.GetFloat64("area.min_match_score"))
+	regionUseCase := usecase.NewRegionUseCase(config.DB, config.Log, config.Rds, areaRepository, provinceRepository,
+		cityRepository, districtRepository, subdistrictRepository, settings)
+	trackingUseCase := usecase.NewTrackingUseCase(config.DB, config.Log, tenantRegistry, config.Rds, trackingLogRepository,
+		NewWaybillRegistry(config.Config), courierRegistry, config.Config.GetInt("tracking.max_detect_attempts"), settings)
+	apiClientUseCase := usecase.NewAPIClientUseCase(config.DB, config.Log, apiClientRepository, merchantRepository,
+		config.Config.GetString("auth.key_pepper"), config.Config.GetDuration("auth.rotation_grace"))
+	merchantUseCase := usecase.NewMerchantUseCase(config.DB, config.Log, merchantRepository, tenantRegistry.Cipher, tenantRegistry)
+	rateLimiter := usecase.NewRateLimiter(config.Rds, config.Log, NewRateLimitConfig(config.Config), settings)
 
 	// setup controller
-	healthCheckController := http.NewHealthCheckController(config.Log)
+	healthCheckController := http.NewHealthCheckController(config.Log, map[string]http.ProviderHealthChecker{
+		usecase.ProviderBiteship: biteshipClient.Breaker(),
+	})
 	courierRateController := http.NewCourierRateController(config.Log, shippingUseCase)
 	trackingController := http.NewTrackingController(config.Log, trackingUseCase)
+	quoteController := http.NewQuoteController(config.Log, quoteUseCase)
+	bulkRateController := http.NewBulkRateController(config.Log, bulkRateUseCase)
+	packingController := http.NewPackingController(config.Log, packingUseCase)
+	areaAdminController := http.NewAreaAdminController(config.Log, areaAdminUseCase)
+	regionController := http.NewRegionController(config.Log, regionUseCase)
+	addressController := http.NewAddressController(config.Log, addressUseCase)
+	apiClientController := http.NewAPIClientController(config.Log, apiClientUseCase)
+	merchantController := http.NewMerchantController(config.Log, merchantUseCase)
 
 	// setup middleware
 	traceIDMiddleware := middleware.TraceIDMiddleware()
+	apiKeyMiddleware := middleware.APIKeyMiddleware(apiClientUseCase)
+	rateLimitMiddleware := middleware.RateLimitMiddleware(rateLimiter)
 
 	routeConfig := route.RouteConfig{
 		App:                   config.App,
 		HealthCheckController: healthCheckController,
 		CourierRateController: courierRateController,
 		TrackingController:    trackingController,
+		QuoteController:       quoteController,
+		BulkRateController:    bulkRateController,
+		PackingController:     packingController,
+		AreaAdminController:   areaAdminController,
+		RegionController:      regionController,
+		AddressController:     addressController,
+		APIClientController:   apiClientController,
+		MerchantController:    merchantController,
 		TraceIDMiddleware:     traceIDMiddleware,
+		APIKeyMiddleware:      apiKeyMiddleware,
+		RateLimitMiddleware:   rateLimitMiddleware,
 	}
 
 	routeConfig.Setup()
+
+	// start background workers
+	courierRegistry.Start(context.Background())
+	bulkRateUseCase.Start(context.Background())
+	areaSearchIndex.Start(context.Background())
+	NewReloader(config.Config, config.ConfigFiles, config.Log, settings, biteshipClient).Start(context.Background())
 }
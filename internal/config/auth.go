@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"shipping-gateway/internal/secret"
+	"shipping-gateway/internal/usecase"
+)
+
+func NewRateLimitConfig(config *viper.Viper) usecase.RateLimitConfig {
+	var groupLimits map[string]int
+	if err := config.UnmarshalKey("rate_limit.groups", &groupLimits); err != nil {
+		panic(fmt.Errorf("invalid rate_limit.groups config: %w", err))
+	}
+
+	rateLimitConfig := usecase.RateLimitConfig{
+		Window:       config.GetDuration("rate_limit.window"),
+		DefaultLimit: config.GetInt("rate_limit.default_limit"),
+		GroupLimits:  groupLimits,
+		MonthlyQuota: config.GetInt("rate_limit.monthly_quota"),
+	}
+	if rateLimitConfig.Window <= 0 || rateLimitConfig.DefaultLimit <= 0 {
+		panic(fmt.Errorf("invalid rate_limit config: window and default_limit must be positive"))
+	}
+	return rateLimitConfig
+}
+
+// NewSecretCipher returns the cipher of stored provider credentials, using the master keys from the environment.
+// Without a master key the platform still runs, but merchant credentials cannot be read or stored.
+func NewSecretCipher(log *logrus.Logger) *secret.Cipher {
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		panic(fmt.Errorf("invalid master key: %w", err))
+	}
+	if keyring.Current() == "" {
+		log.Warnf("No master key configured, merchant credentials are unavailable: %v", secret.ErrNoMasterKey)
+	}
+	return secret.NewCipher(keyring)
+}
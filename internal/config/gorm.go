@@ -54,12 +54,36 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 	AutoMigrate(db,
 		entity.Area{},
 		entity.Courier{},
+		entity.CourierAlias{},
 		entity.CourierService{},
 		entity.ShipmentTrackingLog{},
+		entity.PricingRule{},
+		entity.Quote{},
+		entity.BulkRateJob{},
+		entity.Box{},
+		entity.Province{},
+		entity.City{},
+		entity.District{},
+		entity.Subdistrict{},
+		entity.AreaAudit{},
+		entity.APIClient{},
+		entity.Merchant{},
 	)
+	dropLegacyIndexes(db, log)
 	return db
 }
 
+// dropLegacyIndexes removes indexes AutoMigrate leaves behind when a column stops being unique
+func dropLegacyIndexes(db *gorm.DB, log *logrus.Logger) {
+	// several subdistricts map to the same provider area, so external_id is no longer unique
+	if db.Migrator().HasIndex(&entity.Area{}, "uni_areas_external_id") {
+		if err := db.Migrator().DropIndex(&entity.Area{}, "uni_areas_external_id"); err != nil {
+			panic(fmt.Sprintf("failed to drop unique index of areas.external_id: %v", err))
+		}
+		log.Info("Dropped unique index of areas.external_id")
+	}
+}
+
 func AutoMigrate(db *gorm.DB, models ...interface{}) {
 	if err := db.AutoMigrate(models...); err != nil {
 		panic(fmt.Sprintf("failed to auto migrate: %v", err))
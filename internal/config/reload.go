@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"os"
+	"os/signal"
+	"reflect"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/usecase"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// reloadableKeys are applied to the running gateway when the config is reloaded, every other key needs a restart
+var reloadableKeys = []string{
+	"log.level",
+	"shipping.rate_cache_ttl",
+	"shipping.send_chargeable_weight",
+	"tracking.refetch_interval",
+	"region.cache_ttl",
+	"rate_limit.enabled",
+	"area.search.enabled",
+	"biteship.timeout",
+	"biteship.timeouts",
+}
+
+// reloadDebounce groups the several write events an editor makes when saving a file into one reload
+const reloadDebounce = 500 * time.Millisecond
+
+func NewRuntimeSettings(config *viper.Viper) usecase.RuntimeSettings {
+	return usecase.RuntimeSettings{
+		RateCacheTTL:            config.GetDuration("shipping.rate_cache_ttl"),
+		SendChargeableWeight:    config.GetBool("shipping.send_chargeable_weight"),
+		TrackingRefetchInterval: config.GetDuration("tracking.refetch_interval"),
+		RegionCacheTTL:          config.GetDuration("region.cache_ttl"),
+		RateLimitEnabled:        config.GetBool("rate_limit.enabled"),
+		AreaSearchEnabled:       config.GetBool("area.search.enabled"),
+	}
+}
+
+// Reloader re-reads the config when a config file changes or the process receives SIGHUP, and applies the
+// reloadable keys to the running gateway. A config that fails validation is rejected and the running one kept.
+type Reloader struct {
+	Log      *logrus.Logger
+	Settings *usecase.Settings
+	Biteship *biteship.Client
+
+	files   Files
+	started *viper.Viper // Config the gateway started with, still in use for every key that needs a restart
+	mu      sync.Mutex
+	applied map[string]any // Values of the reloadable keys in use
+	timer   *time.Timer
+}
+
+func NewReloader(config *viper.Viper, files Files, log *logrus.Logger, settings *usecase.Settings,
+	biteshipClient *biteship.Client) *Reloader {
+	return &Reloader{
+		Log:      log,
+		Settings: settings,
+		Biteship: biteshipClient,
+		files:    files,
+		started:  config,
+		applied:  reloadableValues(config),
+	}
+}
+
+// Start watches the config and profile files and listens for SIGHUP until the context is done
+func (r *Reloader) Start(ctx context.Context) {
+	for _, file := range []string{r.files.Config, r.files.Profile} {
+		if file == "" {
+			continue
+		}
+		watcher := viper.New()
+		watcher.SetConfigFile(file)
+		watcher.SetConfigType("yaml")
+		watcher.OnConfigChange(func(event fsnotify.Event) {
+			r.schedule(fmt.Sprintf("change of %s", event.Name))
+		})
+		watcher.WatchConfig()
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				r.Reload("SIGHUP")
+			}
+		}
+	}()
+}
+
+// schedule reloads the config once the file has stopped changing
+func (r *Reloader) schedule(trigger string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.timer = time.AfterFunc(reloadDebounce, func() {
+		r.Reload(trigger)
+	})
+}
+
+// Reload reads and validates the config and applies its reloadable keys, logging every key that changed
+func (r *Reloader) Reload(trigger string) {
+	config, err := loadConfig(r.files)
+	if err != nil {
+		r.Log.Errorf("Config reload on %s rejected, keeping the running config: %v", trigger, err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := reloadableValues(config)
+	changes := make([]string, 0)
+	for _, key := range reloadableKeys {
+		oldValue, newValue := fmt.Sprint(r.applied[key]), fmt.Sprint(values[key])
+		if oldValue != newValue {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, oldValue, newValue))
+		}
+	}
+
+	// keys needing a restart are compared with the config the gateway started with, so they keep being reported
+	// until the restart. Values are left out as they may be resolved secrets.
+	restartKeys := make([]string, 0)
+	for _, key := range changedKeys(r.started, config) {
+		if !isReloadable(key) {
+			restartKeys = append(restartKeys, key)
+		}
+	}
+
+	if len(changes) == 0 && len(restartKeys) == 0 {
+		r.Log.Infof("Config reloaded on %s, nothing changed", trigger)
+		return
+	}
+
+	// the level is known to be valid, ValidateConfig checked it
+	logLevel, _ := logrus.ParseLevel(config.GetString("log.level"))
+	r.Log.SetLevel(logLevel)
+	r.Settings.Store(NewRuntimeSettings(config))
+	r.Biteship.SetTimeouts(biteship.TimeoutsFromConfig(config))
+	r.applied = values
+
+	if len(changes) > 0 {
+		r.Log.Infof("Config reloaded on %s, applied: %s", trigger, strings.Join(changes, ", "))
+	}
+	if len(restartKeys) > 0 {
+		r.Log.Warnf("Config reloaded on %s, changes to %s need a restart", trigger, strings.Join(restartKeys, ", "))
+	}
+}
+
+// reloadableValues returns the value of every reloadable key of the config
+func reloadableValues(config *viper.Viper) map[string]any {
+	values := make(map[string]any, len(reloadableKeys))
+	for _, key := range reloadableKeys {
+		values[key] = config.Get(key)
+	}
+	return values
+}
+
+// changedKeys returns the sorted keys whose value differs between the two configs
+func changedKeys(previous, next *viper.Viper) []string {
+	keys := make(map[string]bool)
+	for _, key := range previous.AllKeys() {
+		keys[key] = true
+	}
+	for _, key := range next.AllKeys() {
+		keys[key] = true
+	}
+
+	changed := make([]string, 0)
+	for key := range keys {
+		if !reflect.DeepEqual(previous.Get(key), next.Get(key)) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// isReloadable reports whether the key, or the map holding it, is reloadable
+func isReloadable(key string) bool {
+	for _, reloadable := range reloadableKeys {
+		if key == reloadable || strings.HasPrefix(key, reloadable+".") {
+			return true
+		}
+	}
+	return false
+}
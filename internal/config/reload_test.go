@@ -0,0 +1,129 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"io"
+	"os"
+	"path/filepath"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/usecase"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+web:
+  port: 8080
+db:
+  host: %DB_HOST%
+  port: 3306
+  user: gateway
+  name: gateway
+redis:
+  host: localhost
+  port: 6379
+log:
+  level: %LOG_LEVEL%
+biteship:
+  base_url: https://api.biteship.com
+  api_key: biteship_test_key
+quote:
+  signing_key: kN3v9fVQ0b1XhUeO2s8R
+auth:
+  key_pepper: Xq7cL2pW9mZ4tY6b
+rate_limit:
+  enabled: %RATE_LIMIT%
+area:
+  search:
+    enabled: true
+`
+
+func writeTestConfig(t *testing.T, file, dbHost, logLevel, rateLimit string) {
+	t.Helper()
+	content := strings.NewReplacer("%DB_HOST%", dbHost, "%LOG_LEVEL%", logLevel, "%RATE_LIMIT%", rateLimit).Replace(testConfig)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestReloaderReload(t *testing.T) {
+	files := Files{Config: filepath.Join(t.TempDir(), "config.yaml")}
+	writeTestConfig(t, files.Config, "db-1", "info", "true")
+	config, err := loadConfig(files)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.InfoLevel)
+	settings := usecase.NewSettings(NewRuntimeSettings(config))
+	quiet := logrus.New()
+	quiet.SetOutput(io.Discard)
+	reloader := NewReloader(config, files, log, settings, biteship.NewClient(config, quiet))
+
+	warnings := func() []string {
+		messages := make([]string, 0)
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.WarnLevel {
+				messages = append(messages, entry.Message)
+			}
+		}
+		hook.Reset()
+		return messages
+	}
+
+	// a reloadable and a restart-only key change
+	writeTestConfig(t, files.Config, "db-2", "info", "false")
+	reloader.Reload("test")
+	if settings.Load().RateLimitEnabled {
+		t.Errorf("rate_limit.enabled = true after reload, want false")
+	}
+	if got := warnings(); len(got) != 1 || !strings.Contains(got[0], "db.host") || strings.Contains(got[0], "db-2") {
+		t.Errorf("warnings = %q, want db.host named as needing a restart without its value", got)
+	}
+
+	// the restart-only key is still not applied, so it keeps being reported
+	writeTestConfig(t, files.Config, "db-2", "debug", "false")
+	reloader.Reload("test")
+	if log.GetLevel() != logrus.DebugLevel {
+		t.Errorf("log level = %v after reload, want debug", log.GetLevel())
+	}
+	if got := warnings(); len(got) != 1 || !strings.Contains(got[0], "db.host") {
+		t.Errorf("warnings = %q, want db.host still needing a restart", got)
+	}
+
+	// reverting the restart-only key leaves nothing to report
+	writeTestConfig(t, files.Config, "db-1", "debug", "false")
+	reloader.Reload("test")
+	if got := warnings(); len(got) != 0 {
+		t.Errorf("warnings = %q, want none once db.host is back to its running value", got)
+	}
+
+	// an invalid config is rejected and the running settings kept
+	writeTestConfig(t, files.Config, "db-1", "verbose", "true")
+	reloader.Reload("test")
+	if log.GetLevel() != logrus.DebugLevel || settings.Load().RateLimitEnabled {
+		t.Errorf("invalid config applied: log level %v, rate limit %v", log.GetLevel(), settings.Load().RateLimitEnabled)
+	}
+}
+
+func TestReloadableValues(t *testing.T) {
+	files := Files{Config: filepath.Join(t.TempDir(), "config.yaml")}
+	writeTestConfig(t, files.Config, "db-1", "info", "true")
+	config, err := loadConfig(files)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	values := reloadableValues(config)
+	if len(values) != len(reloadableKeys) {
+		t.Errorf("reloadableValues() has %d keys, want %d", len(values), len(reloadableKeys))
+	}
+	if _, ok := values["db.host"]; ok {
+		t.Errorf("reloadableValues() holds db.host, which needs a restart")
+	}
+	if !isReloadable("biteship.timeouts.rates") || isReloadable("biteship.timeout_extra") {
+		t.Errorf("isReloadable() must match reloadable keys and the keys of reloadable maps only")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"shipping-gateway/internal/usecase"
+	"strings"
+	"time"
+)
+
+func NewShippingConfig(config *viper.Viper) usecase.ShippingConfig {
+	courierDivisors := make(map[string]int)
+	for code := range config.GetStringMap("shipping.volumetric_divisor.couriers") {
+		courierDivisors[strings.ToLower(code)] = config.GetInt("shipping.volumetric_divisor.couriers." + code)
+	}
+
+	var insuranceFees, codFees usecase.FeeSchedules
+	if err := config.UnmarshalKey("shipping.fees.insurance", &insuranceFees); err != nil {
+		panic(fmt.Errorf("invalid shipping.fees.insurance config: %w", err))
+	}
+	if err := config.UnmarshalKey("shipping.fees.cod", &codFees); err != nil {
+		panic(fmt.Errorf("invalid shipping.fees.cod config: %w", err))
+	}
+
+	return usecase.ShippingConfig{
+		VolumetricDivisor: config.GetInt("shipping.volumetric_divisor.default"),
+		CourierDivisors:   courierDivisors,
+		RecommendWeights: usecase.RecommendWeights{
+			Price:  config.GetFloat64("shipping.recommendation.weights.price"),
+			Speed:  config.GetFloat64("shipping.recommendation.weights.speed"),
+			OnTime: config.GetFloat64("shipping.recommendation.weights.on_time"),
+		},
+		DefaultOnTimeRate: config.GetFloat64("shipping.recommendation.default_on_time_rate"),
+		InsuranceFees:     insuranceFees,
+		CODFees:           codFees,
+		BatchMaxLegs:      config.GetInt("shipping.batch.max_legs"),
+		BatchConcurrency:  config.GetInt("shipping.batch.concurrency"),
+		InstantCouriers:   config.GetStringSlice("shipping.instant_couriers"),
+	}
+}
+
+func NewBulkRateConfig(config *viper.Viper) usecase.BulkRateConfig {
+	rowsPerSecond := config.GetFloat64("bulk_rate.rows_per_second")
+	if rowsPerSecond <= 0 {
+		rowsPerSecond = 1
+	}
+
+	return usecase.BulkRateConfig{
+		StorageDir:    config.GetString("bulk_rate.storage_dir"),
+		MaxRows:       config.GetInt("bulk_rate.max_rows"),
+		Workers:       config.GetInt("bulk_rate.workers"),
+		RowInterval:   time.Duration(float64(time.Second) / rowsPerSecond),
+		ProgressEvery: config.GetInt("bulk_rate.progress_every"),
+		QueueSize:     config.GetInt("bulk_rate.queue_size"),
+	}
+}
+
+func NewAreaSearchConfig(config *viper.Viper) usecase.AreaSearchConfig {
+	return usecase.AreaSearchConfig{
+		MinScore:        config.GetFloat64("area.search.min_score"),
+		RefreshInterval: config.GetDuration("area.search.refresh_interval"),
+		Aliases:         config.GetStringMapString("area.search.aliases"),
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"shipping-gateway/internal/usecase"
+)
+
+func NewWaybillRegistry(config *viper.Viper) *usecase.WaybillRegistry {
+	var patterns map[string][]usecase.WaybillPattern
+	if err := config.UnmarshalKey("tracking.waybill_patterns", &patterns); err != nil {
+		panic(fmt.Errorf("invalid tracking.waybill_patterns config: %w", err))
+	}
+
+	registry, err := usecase.NewWaybillRegistry(patterns)
+	if err != nil {
+		panic(fmt.Errorf("invalid tracking.waybill_patterns config: %w", err))
+	}
+	return registry
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"math"
+	"shipping-gateway/internal/secret"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type configKind string
+
+const (
+	kindString   configKind = "a string"
+	kindInt      configKind = "an integer"
+	kindFloat    configKind = "a number"
+	kindBool     configKind = "a boolean"
+	kindDuration configKind = "a duration such as 30s or 5m"
+	kindLogLevel configKind = "one of trace, debug, info, warn, error, fatal or panic"
+)
+
+// secretPlaceholderPrefix starts placeholder values, such as change-me-signing-key, left where a secret belongs
+const secretPlaceholderPrefix = "change-me"
+
+// configRule describes a key the application reads
+type configRule struct {
+	Key      string
+	Kind     configKind
+	Required bool    // The key must be set to a non-empty value
+	Secret   bool    // The value must be a real secret, not a change-me placeholder
+	Min      float64 // Smallest allowed number, checked when Min < Max
+	Max      float64 // Largest allowed number, checked when Min < Max
+}
+
+// configRules lists the keys whose wrong value would otherwise be read as a silent zero
+var configRules = []configRule{
+	{Key: "web.port", Kind: kindInt, Required: true, Min: 1, Max: 65535},
+	{Key: "db.host", Kind: kindString, Required: true},
+	{Key: "db.port", Kind: kindInt, Required: true, Min: 1, Max: 65535},
+	{Key: "db.user", Kind: kindString, Required: true},
+	{Key: "db.name", Kind: kindString, Required: true},
+	{Key: "db.debug", Kind: kindBool},
+	{Key: "redis.host", Kind: kindString, Required: true},
+	{Key: "redis.port", Kind: kindInt, Required: true, Min: 1, Max: 65535},
+	{Key: "redis.db", Kind: kindInt, Min: 0, Max: 15},
+	{Key: "log.level", Kind: kindLogLevel, Required: true},
+	{Key: "log.console_enabled", Kind: kindBool},
+	{Key: "log.max_size", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "log.max_backups", Kind: kindInt, Min: 0, Max: math.MaxInt32},
+	{Key: "log.max_age", Kind: kindInt, Min: 0, Max: math.MaxInt32},
+	{Key: "biteship.base_url", Kind: kindString, Required: true},
+	{Key: "biteship.api_key", Kind: kindString, Required: true},
+	{Key: "biteship.timeout", Kind: kindDuration},
+	{Key: "biteship.retry.max_attempts", Kind: kindInt, Min: 1, Max: 10},
+	{Key: "biteship.retry.base_delay", Kind: kindDuration},
+	{Key: "biteship.retry.max_delay", Kind: kindDuration},
+	{Key: "biteship.breaker.failure_threshold", Kind: kindInt, Min: 0, Max: math.MaxInt32},
+	{Key: "biteship.breaker.open_timeout", Kind: kindDuration},
+	{Key: "shipping.rate_cache_ttl", Kind: kindDuration, Required: true},
+	{Key: "shipping.volumetric_divisor.default", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "shipping.send_chargeable_weight", Kind: kindBool},
+	{Key: "shipping.recommendation.weights.price", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "shipping.recommendation.weights.speed", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "shipping.recommendation.weights.on_time", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "shipping.recommendation.default_on_time_rate", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "shipping.batch.max_legs", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "shipping.batch.concurrency", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "bulk_rate.storage_dir", Kind: kindString, Required: true},
+	{Key: "bulk_rate.max_rows", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "bulk_rate.workers", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "bulk_rate.rows_per_second", Kind: kindFloat},
+	{Key: "bulk_rate.progress_every", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "bulk_rate.queue_size", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "packing.fill_factor", Kind: kindFloat, Min: 0.01, Max: 1},
+	{Key: "quote.ttl", Kind: kindDuration, Required: true},
+	{Key: "quote.signing_key", Kind: kindString, Required: true, Secret: true},
+	{Key: "area.min_match_score", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "area.search.enabled", Kind: kindBool},
+	{Key: "area.search.min_score", Kind: kindFloat, Min: 0, Max: 1},
+	{Key: "area.search.refresh_interval", Kind: kindDuration},
+	{Key: "region.cache_ttl", Kind: kindDuration},
+	{Key: "courier.refresh_interval", Kind: kindDuration},
+	{Key: "tracking.max_detect_attempts", Kind: kindInt, Min: 0, Max: math.MaxInt32},
+	{Key: "tracking.refetch_interval", Kind: kindDuration},
+	{Key: "auth.key_pepper", Kind: kindString, Required: true, Secret: true},
+	{Key: "auth.rotation_grace", Kind: kindDuration},
+	{Key: "tenant.cache_ttl", Kind: kindDuration},
+	{Key: "rate_limit.enabled", Kind: kindBool},
+	{Key: "rate_limit.window", Kind: kindDuration},
+	{Key: "rate_limit.default_limit", Kind: kindInt, Min: 1, Max: math.MaxInt32},
+	{Key: "rate_limit.monthly_quota", Kind: kindInt, Min: 0, Max: math.MaxInt32},
+}
+
+// ValidateConfig checks every known key and returns all missing or invalid keys at once
+func ValidateConfig(config *viper.Viper) error {
+	problems := make([]string, 0)
+	for _, rule := range configRules {
+		if err := rule.check(config.Get(rule.Key)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", rule.Key, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration, fix the config file or the %s_* environment variables:\n  - %s",
+			EnvPrefix, strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (r configRule) check(value any) error {
+	if value == nil || value == "" {
+		if r.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+
+	var number float64
+	switch r.Kind {
+	case kindString:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v must be %s", value, r.Kind)
+		}
+		// the value itself is left out of the error as it may be a secret
+		if r.Secret && strings.HasPrefix(strings.ToLower(strings.TrimSpace(text)), secretPlaceholderPrefix) {
+			return fmt.Errorf("must be replaced with a random secret, e.g. a %s reference", secret.ReferencePrefix)
+		}
+		return nil
+	case kindBool:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+		if text, ok := value.(string); ok {
+			if _, err := strconv.ParseBool(text); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q must be %s", fmt.Sprint(value), r.Kind)
+	case kindLogLevel:
+		if text, ok := value.(string); ok {
+			if _, err := logrus.ParseLevel(text); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q must be %s", fmt.Sprint(value), r.Kind)
+	case kindDuration:
+		text, ok := value.(string)
+		if ok {
+			if _, err := time.ParseDuration(text); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q must be %s", fmt.Sprint(value), r.Kind)
+	case kindInt:
+		n, ok := toNumber(value)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%q must be %s", fmt.Sprint(value), r.Kind)
+		}
+		number = n
+	case kindFloat:
+		n, ok := toNumber(value)
+		if !ok {
+			return fmt.Errorf("%q must be %s", fmt.Sprint(value), r.Kind)
+		}
+		number = n
+	}
+
+	if r.Min < r.Max && (number < r.Min || number > r.Max) {
+		return fmt.Errorf("%v must be between %v and %v", number, r.Min, r.Max)
+	}
+	return nil
+}
+
+// toNumber reads numbers from the config file, where YAML types them, and from environment variables, which are text
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
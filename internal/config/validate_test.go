@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigRuleCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    configRule
+		value   any
+		wantErr string
+	}{
+		{"required missing", configRule{Kind: kindString, Required: true}, "", "is required"},
+		{"optional missing", configRule{Kind: kindInt, Min: 1, Max: 10}, nil, ""},
+		{"string", configRule{Kind: kindString}, "value", ""},
+		{"not a string", configRule{Kind: kindString}, 5, "must be a string"},
+		{"secret", configRule{Kind: kindString, Secret: true}, "kN3v9fVQ0b1XhUeO2s8R", ""},
+		{"secret placeholder", configRule{Kind: kindString, Secret: true}, "change-me-quote-signing-key", "random secret"},
+		{"secret placeholder in capitals", configRule{Kind: kindString, Secret: true}, " CHANGE-ME ", "random secret"},
+		{"bool from env", configRule{Kind: kindBool}, "true", ""},
+		{"invalid bool", configRule{Kind: kindBool}, "yes please", "must be a boolean"},
+		{"log level", configRule{Kind: kindLogLevel, Required: true}, "warn", ""},
+		{"invalid log level", configRule{Kind: kindLogLevel, Required: true}, "verbose", "must be one of"},
+		{"duration", configRule{Kind: kindDuration}, "1m30s", ""},
+		{"invalid duration", configRule{Kind: kindDuration}, "90", "must be a duration"},
+		{"int in range", configRule{Kind: kindInt, Min: 1, Max: 65535}, 8080, ""},
+		{"int from env", configRule{Kind: kindInt, Min: 1, Max: 65535}, " 8080 ", ""},
+		{"int out of range", configRule{Kind: kindInt, Min: 1, Max: 65535}, 70000, "must be between"},
+		{"fraction for int", configRule{Kind: kindInt}, 1.5, "must be an integer"},
+		{"float", configRule{Kind: kindFloat, Min: 0, Max: 1}, 0.85, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.check(tt.value)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("check(%v) error = %v, want nil", tt.value, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("check(%v) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			if err != nil && tt.rule.Secret && strings.Contains(strings.ToLower(err.Error()), "change-me") {
+				t.Errorf("check(%v) error = %v, want the value left out", tt.value, err)
+			}
+		})
+	}
+}
@@ -1,27 +1,139 @@
 package config
 
 import (
+	"flag"
 	"fmt"
 	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"shipping-gateway/internal/secret"
+	"strings"
 )
 
-// NewViper is a function to load config from config.json
-// You can change the implementation, for example load from env file, consul, etcd, etc
-func NewViper() *viper.Viper {
+const (
+	// EnvPrefix prefixes environment variables overriding config keys, e.g. SHIPPING_GATEWAY_DB_HOST for db.host
+	EnvPrefix = "SHIPPING_GATEWAY"
+	// ConfigFileEnv names the config file when the --config flag is not given
+	ConfigFileEnv = EnvPrefix + "_CONFIG"
+	// ProfileEnv names the profile when the --env flag is not given
+	ProfileEnv = EnvPrefix + "_ENV"
+)
+
+// Files are the config file and the profile file merged over it
+type Files struct {
+	Config  string // Config file
+	Profile string // Profile file merged over the config file, empty without a profile
+}
+
+// ConfigFiles returns the config files named by the --config and --env flags of a command, falling back to the
+// SHIPPING_GATEWAY_CONFIG and SHIPPING_GATEWAY_ENV environment variables and then to ./config.yaml. The profile
+// file is config.<env>.yaml next to the config file.
+func ConfigFiles(configFile, profile string) Files {
+	files := Files{Config: firstNonEmpty(configFile, os.Getenv(ConfigFileEnv), "config.yaml")}
+	if profile = firstNonEmpty(profile, os.Getenv(ProfileEnv)); profile != "" {
+		files.Profile = filepath.Join(filepath.Dir(files.Config), fmt.Sprintf("config.%s.yaml", profile))
+	}
+	return files
+}
+
+// FileFlags adds the --config and --env flags to the flag set of a command. The returned function gives the
+// config files they name once the flags are parsed.
+func FileFlags(flags *flag.FlagSet) func() Files {
+	configFile := flags.String("config", "", "path of the config file, ./config.yaml by default")
+	profile := flags.String("env", "", "profile whose config.<env>.yaml, next to the config file, is merged over it")
+	return func() Files {
+		return ConfigFiles(*configFile, *profile)
+	}
+}
+
+// NewViper loads the config file, merges the profile file of the environment over it and lets SHIPPING_GATEWAY_*
+// environment variables override any key. Secret references are resolved and the result is validated, the
+// process exits with every invalid key listed when it is not usable.
+func NewViper(files Files) *viper.Viper {
+	config, err := loadConfig(files)
+	if err != nil {
+		exitOnConfigError(err)
+	}
+	return config
+}
+
+// loadConfig reads a fresh config the way NewViper does, returning the error instead of exiting
+func loadConfig(files Files) (*viper.Viper, error) {
 	config := viper.New()
+	SetDefaultValues(config)
+
+	config.SetEnvPrefix(EnvPrefix)
+	config.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	config.AutomaticEnv()
 
-	config.SetConfigName("config")
+	config.SetConfigFile(files.Config)
 	config.SetConfigType("yaml")
-	config.AddConfigPath("./")
-	err := config.ReadInConfig()
+	if err := config.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", files.Config, err)
+	}
 
-	SetDefaultValues(config)
+	if files.Profile != "" {
+		config.SetConfigFile(files.Profile)
+		if err := config.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading profile file %s: %w", files.Profile, err)
+		}
+	}
 
-	if err != nil {
-		panic(fmt.Errorf("Fatal error config file: %w \n", err))
+	if err := resolveSecrets(config); err != nil {
+		return nil, fmt.Errorf("error resolving secrets: %w", err)
+	}
+	if err := ValidateConfig(config); err != nil {
+		return nil, err
 	}
 
-	return config
+	return config, nil
+}
+
+// exitOnConfigError stops the process before anything is started with an unusable config
+func exitOnConfigError(err error) {
+	fmt.Fprintf(os.Stderr, "Fatal error config: %v\n", err)
+	os.Exit(1)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// resolveSecrets replaces every secret://<name> value with the secret decrypted from the secrets file,
+// so credentials never have to be written in plaintext in config.yaml
+func resolveSecrets(config *viper.Viper) error {
+	var store *secret.Store
+	var cipher *secret.Cipher
+	for _, key := range config.AllKeys() {
+		reference, ok := config.Get(key).(string)
+		if !ok || !strings.HasPrefix(reference, secret.ReferencePrefix) {
+			continue
+		}
+
+		// the master key and secrets file are only needed when the config references secrets
+		if store == nil {
+			keyring, err := secret.LoadKeyring()
+			if err != nil {
+				return err
+			}
+			if store, err = secret.LoadStore(secret.SecretsFile()); err != nil {
+				return err
+			}
+			cipher = secret.NewCipher(keyring)
+		}
+
+		value, err := store.Resolve(cipher, reference)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		config.Set(key, value)
+	}
+	return nil
 }
 
 func SetDefaultValues(config *viper.Viper) {
@@ -53,5 +165,73 @@ func SetDefaultValues(config *viper.Viper) {
 	config.SetDefault("log.max_age", 30) // in days
 	config.SetDefault("log.compression", true)
 
+	// Shipping Configuration
+	config.SetDefault("shipping.rate_cache_ttl", "30m")
+	config.SetDefault("shipping.volumetric_divisor.default", 6000)
+	config.SetDefault("shipping.send_chargeable_weight", false)
+	config.SetDefault("shipping.recommendation.weights.price", 0.5)
+	config.SetDefault("shipping.recommendation.weights.speed", 0.3)
+	config.SetDefault("shipping.recommendation.weights.on_time", 0.2)
+	config.SetDefault("shipping.recommendation.default_on_time_rate", 0.8)
+	config.SetDefault("shipping.fees.insurance.default.rate", 0.002)
+	config.SetDefault("shipping.fees.insurance.default.minimum", 0)
+	config.SetDefault("shipping.fees.insurance.default.flat", 0)
+	config.SetDefault("shipping.fees.cod.default.rate", 0.03)
+	config.SetDefault("shipping.fees.cod.default.minimum", 2500)
+	config.SetDefault("shipping.fees.cod.default.flat", 0)
+	config.SetDefault("shipping.batch.max_legs", 20)
+	config.SetDefault("shipping.batch.concurrency", 5)
+	config.SetDefault("shipping.instant_couriers", []string{"gojek", "grab", "lalamove", "borzo"})
+
+	// Bulk Rate Configuration
+	config.SetDefault("bulk_rate.storage_dir", "storage/bulk_rates")
+	config.SetDefault("bulk_rate.max_rows", 10000)
+	config.SetDefault("bulk_rate.workers", 1)
+	config.SetDefault("bulk_rate.rows_per_second", 5)
+	config.SetDefault("bulk_rate.progress_every", 25)
+	config.SetDefault("bulk_rate.queue_size", 100)
+
+	// Packing Configuration
+	config.SetDefault("packing.fill_factor", 0.85)
+
+	// Quote Configuration
+	config.SetDefault("quote.ttl", "30m")
+
+	// Area Configuration
+	config.SetDefault("area.min_match_score", 0.75)
+	config.SetDefault("area.search.enabled", true)
+	config.SetDefault("area.search.min_score", 0.85)
+	config.SetDefault("area.search.refresh_interval", "10m")
+
+	// Region Configuration
+	config.SetDefault("region.cache_ttl", "24h")
+
+	// Courier Configuration
+	config.SetDefault("courier.refresh_interval", "5m")
+
+	// Tracking Configuration
+	config.SetDefault("tracking.max_detect_attempts", 3)
+	config.SetDefault("tracking.refetch_interval", "2h")
+
+	// Biteship Configuration
+	config.SetDefault("biteship.timeout", "30s")
+	config.SetDefault("biteship.retry.max_attempts", 3)
+	config.SetDefault("biteship.retry.base_delay", "200ms")
+	config.SetDefault("biteship.retry.max_delay", "2s")
+	config.SetDefault("biteship.breaker.failure_threshold", 5)
+	config.SetDefault("biteship.breaker.open_timeout", "30s")
+
+	// Auth Configuration
+	config.SetDefault("auth.rotation_grace", "24h")
+
+	// Tenant Configuration
+	config.SetDefault("tenant.cache_ttl", "5m")
+
+	// Rate Limit Configuration
+	config.SetDefault("rate_limit.enabled", true)
+	config.SetDefault("rate_limit.window", "1m")
+	config.SetDefault("rate_limit.default_limit", 60)
+	config.SetDefault("rate_limit.monthly_quota", 0)
+
 	// Add more default values as needed
 }
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"shipping-gateway/internal/secret"
+	"strings"
+	"testing"
+)
+
+func TestConfigFiles(t *testing.T) {
+	t.Setenv(ConfigFileEnv, "")
+	t.Setenv(ProfileEnv, "")
+
+	tests := []struct {
+		name       string
+		configFile string
+		profile    string
+		env        map[string]string
+		want       Files
+	}{
+		{"defaults", "", "", nil, Files{Config: "config.yaml"}},
+		{"flags", "/etc/gateway/config.yaml", "prod", nil, Files{Config: "/etc/gateway/config.yaml", Profile: "/etc/gateway/config.prod.yaml"}},
+		{"environment", "", "", map[string]string{ConfigFileEnv: "conf/base.yaml", ProfileEnv: "staging"},
+			Files{Config: "conf/base.yaml", Profile: "conf/config.staging.yaml"}},
+		{"flags over environment", "other.yaml", "", map[string]string{ConfigFileEnv: "conf/base.yaml", ProfileEnv: "staging"},
+			Files{Config: "other.yaml", Profile: "config.staging.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if got := ConfigFiles(tt.configFile, tt.profile); got != tt.want {
+				t.Errorf("ConfigFiles(%q, %q) = %+v, want %+v", tt.configFile, tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigLocalProfile(t *testing.T) {
+	t.Setenv(secret.MasterKeyEnv, "")
+	t.Setenv(secret.MasterKeyFileEnv, "")
+	t.Setenv(secret.SecretsFileEnv, filepath.Join(t.TempDir(), "secrets.yaml"))
+
+	example, err := os.ReadFile("../../config.local.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := filepath.Join(t.TempDir(), "config.local.yaml")
+	files := Files{Config: "../../config.yaml", Profile: profile}
+
+	// the copied example replaces every secret reference, so only its empty values are reported
+	if err = os.WriteFile(profile, example, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = loadConfig(files)
+	for _, key := range []string{"quote.signing_key", "auth.key_pepper", "biteship.api_key"} {
+		if err == nil || !strings.Contains(err.Error(), key+": is required") {
+			t.Errorf("loadConfig() error = %v, want %s reported as required", err, key)
+		}
+	}
+
+	filled := strings.NewReplacer(
+		`signing_key: ""`, `signing_key: "local-signing-key"`,
+		`key_pepper: ""`, `key_pepper: "local-key-pepper"`,
+		`api_key: ""`, `api_key: "local-biteship-key"`,
+	).Replace(string(example))
+	if err = os.WriteFile(profile, []byte(filled), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfig(files)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v, want the filled local profile to load without a master key", err)
+	}
+	if got := config.GetString("quote.signing_key"); got != "local-signing-key" {
+		t.Errorf("quote.signing_key = %q, want the local profile value", got)
+	}
+}
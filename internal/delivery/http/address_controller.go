@@ -0,0 +1,52 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type AddressController struct {
+	Log            *logrus.Logger
+	AddressUseCase *usecase.AddressUseCase
+}
+
+func NewAddressController(log *logrus.Logger, addressUseCase *usecase.AddressUseCase) *AddressController {
+	return &AddressController{
+		Log:            log,
+		AddressUseCase: addressUseCase,
+	}
+}
+
+func (ac *AddressController) Parse(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.AddressParseRequest
+	if err := validator.ValidateAddressParseRequest(c, &req); err != nil {
+		log.Errorf("Invalid request parameters, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := ac.AddressUseCase.ParseAddress(c, req.Address)
+	if ucResp.StatusCode != http.StatusOK {
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.ParsedAddressResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type APIClientController struct {
+	Log              *logrus.Logger
+	APIClientUseCase *usecase.APIClientUseCase
+}
+
+func NewAPIClientController(log *logrus.Logger, apiClientUseCase *usecase.APIClientUseCase) *APIClientController {
+	return &APIClientController{
+		Log:              log,
+		APIClientUseCase: apiClientUseCase,
+	}
+}
+
+func (ac *APIClientController) CreateClient(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.APIClientCreateRequest
+	if err := validator.ValidateAPIClientCreateRequest(c, &req); err != nil {
+		log.Errorf("Invalid API client request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := ac.APIClientUseCase.CreateClient(c, req)
+	ac.respondClient(c, ucResp, resp)
+}
+
+func (ac *APIClientController) ListClients(c *gin.Context) {
+	ucResp, resp := ac.APIClientUseCase.ListClients(c)
+	if ucResp.StatusCode != http.StatusOK {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.APIClientListResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: resp,
+	})
+}
+
+func (ac *APIClientController) UpdateClient(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.APIClientUpdateRequest
+	if err := validator.ValidateAPIClientUpdateRequest(c, &req); err != nil {
+		log.Errorf("Invalid API client request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := ac.APIClientUseCase.UpdateClient(c, c.Param("id"), req)
+	ac.respondClient(c, ucResp, resp)
+}
+
+func (ac *APIClientController) RotateKey(c *gin.Context) {
+	ucResp, resp := ac.APIClientUseCase.RotateKey(c, c.Param("id"))
+	ac.respondClient(c, ucResp, resp)
+}
+
+func (ac *APIClientController) respondClient(c *gin.Context, ucResp *model.ServiceResponse, resp *model.APIClientResponse) {
+	if resp == nil {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.APIClientResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
+
+func (ac *APIClientController) abort(c *gin.Context, ucResp *model.ServiceResponse) {
+	ac.Log.WithField("traceId", c.Value("traceId")).Errorf("API client request failed: %s", ucResp.Message)
+	c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+		Status:  "failed",
+		Code:    ucResp.StatusCode,
+		Message: ucResp.Message,
+	})
+}
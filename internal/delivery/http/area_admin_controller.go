@@ -0,0 +1,195 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type AreaAdminController struct {
+	Log              *logrus.Logger
+	AreaAdminUseCase *usecase.AreaAdminUseCase
+}
+
+func NewAreaAdminController(log *logrus.Logger, areaAdminUseCase *usecase.AreaAdminUseCase) *AreaAdminController {
+	return &AreaAdminController{
+		Log:              log,
+		AreaAdminUseCase: areaAdminUseCase,
+	}
+}
+
+func (ac *AreaAdminController) ListAreas(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	var filter model.AreaFilter
+	if err := validator.ValidateAreaFilter(c, &filter); err != nil {
+		log.Errorf("Invalid area filter, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp, paging := ac.AreaAdminUseCase.ListAreas(c, filter)
+	if ucResp.StatusCode != http.StatusOK {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.AreaListResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: resp,
+		Meta: *paging,
+	})
+}
+
+func (ac *AreaAdminController) GetArea(c *gin.Context) {
+	id, ok := ac.areaID(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := ac.AreaAdminUseCase.GetArea(c, id)
+	ac.respondArea(c, ucResp, resp)
+}
+
+func (ac *AreaAdminController) GetAreaAudits(c *gin.Context) {
+	id, ok := ac.areaID(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := ac.AreaAdminUseCase.GetAreaAudits(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.AreaAuditListResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: resp,
+	})
+}
+
+func (ac *AreaAdminController) CreateArea(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.AreaCreateRequest
+	if err := validator.ValidateAreaCreateRequest(c, &req); err != nil {
+		log.Errorf("Invalid area request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+	req.Actor = c.GetString("actor")
+
+	ucResp, resp := ac.AreaAdminUseCase.CreateArea(c, req)
+	ac.respondArea(c, ucResp, resp)
+}
+
+func (ac *AreaAdminController) OverrideArea(c *gin.Context) {
+	log := ac.Log.WithField("traceId", c.Value("traceId"))
+
+	id, ok := ac.areaID(c)
+	if !ok {
+		return
+	}
+
+	var req model.AreaOverrideRequest
+	if err := validator.ValidateAreaOverrideRequest(c, &req); err != nil {
+		log.Errorf("Invalid area request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+	req.Actor = c.GetString("actor")
+
+	ucResp, resp := ac.AreaAdminUseCase.OverrideArea(c, id, req)
+	ac.respondArea(c, ucResp, resp)
+}
+
+func (ac *AreaAdminController) ReResolveArea(c *gin.Context) {
+	id, req, ok := ac.changeRequest(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := ac.AreaAdminUseCase.ReResolveArea(c, id, req)
+	ac.respondArea(c, ucResp, resp)
+}
+
+func (ac *AreaAdminController) DeleteArea(c *gin.Context) {
+	id, req, ok := ac.changeRequest(c)
+	if !ok {
+		return
+	}
+
+	ucResp := ac.AreaAdminUseCase.DeleteArea(c, id, req)
+	if ucResp.StatusCode != http.StatusOK {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.Response{
+		Status:  "success",
+		Code:    ucResp.StatusCode,
+		Message: "success",
+	})
+}
+
+func (ac *AreaAdminController) areaID(c *gin.Context) (uint, bool) {
+	id, err := validator.ValidateAreaID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (ac *AreaAdminController) changeRequest(c *gin.Context) (uint, model.AreaChangeRequest, bool) {
+	var req model.AreaChangeRequest
+	id, ok := ac.areaID(c)
+	if !ok {
+		return 0, req, false
+	}
+
+	if err := validator.ValidateAreaChangeRequest(c, &req); err != nil {
+		ac.Log.WithField("traceId", c.Value("traceId")).Errorf("Invalid area request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return 0, req, false
+	}
+	req.Actor = c.GetString("actor")
+	return id, req, true
+}
+
+func (ac *AreaAdminController) respondArea(c *gin.Context, ucResp *model.ServiceResponse, resp *model.AreaResponse) {
+	if resp == nil {
+		ac.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.AreaResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
+
+func (ac *AreaAdminController) abort(c *gin.Context, ucResp *model.ServiceResponse) {
+	ac.Log.WithField("traceId", c.Value("traceId")).Errorf("Area admin request failed: %s", ucResp.Message)
+	c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+		Status:  "failed",
+		Code:    ucResp.StatusCode,
+		Message: ucResp.Message,
+	})
+}
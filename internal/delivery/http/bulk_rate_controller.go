@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type BulkRateController struct {
+	Log             *logrus.Logger
+	BulkRateUseCase *usecase.BulkRateUseCase
+}
+
+func NewBulkRateController(log *logrus.Logger, bulkRateUseCase *usecase.BulkRateUseCase) *BulkRateController {
+	return &BulkRateController{
+		Log:             log,
+		BulkRateUseCase: bulkRateUseCase,
+	}
+}
+
+func (bc *BulkRateController) Upload(c *gin.Context) {
+	log := bc.Log.WithField("traceId", c.Value("traceId"))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Errorf("Invalid upload, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Message: "field 'file' must contain a CSV file", Status: "failed"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Errorf("Error opening uploaded file: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Message: "failed to open uploaded file", Status: "failed"})
+		return
+	}
+	defer file.Close()
+
+	ucResp, resp := bc.BulkRateUseCase.CreateJob(c, fileHeader.Filename, file)
+	if resp == nil {
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.BulkRateJobResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		},
+		Data: *resp,
+	})
+}
+
+func (bc *BulkRateController) GetJob(c *gin.Context) {
+	id := c.Param("id")
+	ucResp, resp := bc.BulkRateUseCase.GetJob(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.BulkRateJobResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
+
+func (bc *BulkRateController) DownloadResult(c *gin.Context) {
+	id := c.Param("id")
+	format := c.DefaultQuery("format", "csv")
+
+	ucResp, content := bc.BulkRateUseCase.GetResult(c, id, format)
+	if ucResp.StatusCode != http.StatusOK {
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rates_%s.%s"`, id, format))
+	c.Data(http.StatusOK, contentType, content)
+}
@@ -7,6 +7,7 @@ import (
 	"shipping-gateway/internal/delivery/http/validator"
 	"shipping-gateway/internal/model"
 	"shipping-gateway/internal/usecase"
+	"strings"
 )
 
 type CourierRateController struct {
@@ -32,6 +33,9 @@ func (c *CourierRateController) GetCourierRates(ctx *gin.Context) {
 		return
 	}
 
+	// Clients can force fresh provider rates with "Cache-Control: no-cache"
+	req.BypassCache = strings.Contains(strings.ToLower(ctx.GetHeader("Cache-Control")), "no-cache")
+
 	ucResp, resp := c.ShippingUseCase.GetCourierRates(ctx, &req)
 	if ucResp.StatusCode != http.StatusOK {
 		ctx.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
@@ -43,3 +47,31 @@ func (c *CourierRateController) GetCourierRates(ctx *gin.Context) {
 	}
 	ctx.JSON(ucResp.StatusCode, resp)
 }
+
+func (c *CourierRateController) GetBatchCourierRates(ctx *gin.Context) {
+	log := c.Log.WithField("traceId", ctx.Value("traceId"))
+
+	var req model.BatchRateRequest
+	err := validator.ValidateBatchRateRequest(ctx, &req, c.ShippingUseCase.Config.BatchMaxLegs)
+	if err != nil {
+		log.Errorf("Invalid request parameters, error: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	bypassCache := strings.Contains(strings.ToLower(ctx.GetHeader("Cache-Control")), "no-cache")
+	for i := range req.Legs {
+		req.Legs[i].BypassCache = bypassCache
+	}
+
+	ucResp, resp := c.ShippingUseCase.GetBatchCourierRates(ctx, &req)
+	if ucResp.StatusCode != http.StatusOK {
+		ctx.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+	ctx.JSON(ucResp.StatusCode, resp)
+}
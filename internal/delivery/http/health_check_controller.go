@@ -4,23 +4,42 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"shipping-gateway/internal/model"
 )
 
+// ProviderHealthChecker reports the health of a provider client
+type ProviderHealthChecker interface {
+	Health() model.ProviderHealth
+}
+
 type HealthCheckController struct {
-	Log *logrus.Logger
+	Log       *logrus.Logger
+	Providers map[string]ProviderHealthChecker
 }
 
-func NewHealthCheckController(log *logrus.Logger) *HealthCheckController {
+func NewHealthCheckController(log *logrus.Logger, providers map[string]ProviderHealthChecker) *HealthCheckController {
 	return &HealthCheckController{
-		Log: log,
+		Log:       log,
+		Providers: providers,
 	}
 }
 
 func (h *HealthCheckController) HealthCheck(c *gin.Context) {
 	h.Log.Info("Health check endpoint hit")
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"message": "Service is running",
-	})
+	resp := model.HealthCheckResponse{
+		Status:    "ok",
+		Message:   "Service is running",
+		Providers: make(map[string]model.ProviderHealth),
+	}
+	for name, provider := range h.Providers {
+		health := provider.Health()
+		if health.Circuit != "closed" {
+			resp.Status = "degraded"
+			resp.Message = "Service is running, calls to " + name + " are failing fast"
+		}
+		resp.Providers[name] = health
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type MerchantController struct {
+	Log             *logrus.Logger
+	MerchantUseCase *usecase.MerchantUseCase
+}
+
+func NewMerchantController(log *logrus.Logger, merchantUseCase *usecase.MerchantUseCase) *MerchantController {
+	return &MerchantController{
+		Log:             log,
+		MerchantUseCase: merchantUseCase,
+	}
+}
+
+func (mc *MerchantController) CreateMerchant(c *gin.Context) {
+	log := mc.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.MerchantCreateRequest
+	if err := validator.ValidateMerchantCreateRequest(c, &req); err != nil {
+		log.Errorf("Invalid merchant request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := mc.MerchantUseCase.CreateMerchant(c, req)
+	mc.respondMerchant(c, ucResp, resp)
+}
+
+func (mc *MerchantController) ListMerchants(c *gin.Context) {
+	ucResp, resp := mc.MerchantUseCase.ListMerchants(c)
+	if ucResp.StatusCode != http.StatusOK {
+		mc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.MerchantListResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: resp,
+	})
+}
+
+func (mc *MerchantController) GetMerchant(c *gin.Context) {
+	ucResp, resp := mc.MerchantUseCase.GetMerchant(c, c.Param("id"))
+	mc.respondMerchant(c, ucResp, resp)
+}
+
+func (mc *MerchantController) UpdateMerchant(c *gin.Context) {
+	log := mc.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.MerchantUpdateRequest
+	if err := validator.ValidateMerchantUpdateRequest(c, &req); err != nil {
+		log.Errorf("Invalid merchant request, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := mc.MerchantUseCase.UpdateMerchant(c, c.Param("id"), req)
+	mc.respondMerchant(c, ucResp, resp)
+}
+
+func (mc *MerchantController) respondMerchant(c *gin.Context, ucResp *model.ServiceResponse, resp *model.MerchantResponse) {
+	if resp == nil {
+		mc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.MerchantResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
+
+func (mc *MerchantController) abort(c *gin.Context, ucResp *model.ServiceResponse) {
+	mc.Log.WithField("traceId", c.Value("traceId")).Errorf("Merchant request failed: %s", ucResp.Message)
+	c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+		Status:  "failed",
+		Code:    ucResp.StatusCode,
+		Message: ucResp.Message,
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+	"strings"
+)
+
+// This Middleware authenticates the API key sent in the Authorization (Bearer) or X-API-Key header and attaches
+// the API client to the context. The client name is also set as the actor recorded in audit trails, and the merchant
+// of the client as the tenant every request is scoped to.
+
+func APIKeyMiddleware(apiClientUseCase *usecase.APIClientUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if auth := c.GetHeader("Authorization"); apiKey == "" && auth != "" {
+			scheme, token, _ := strings.Cut(auth, " ")
+			if strings.EqualFold(scheme, "Bearer") {
+				apiKey = strings.TrimSpace(token)
+			}
+		}
+		if apiKey == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="shipping-gateway"`)
+			abort(c, http.StatusUnauthorized, "API key is required")
+			return
+		}
+
+		ucResp, client := apiClientUseCase.Authenticate(c, apiKey)
+		if client == nil {
+			if ucResp.StatusCode == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", `Bearer realm="shipping-gateway", error="invalid_token"`)
+			}
+			abort(c, ucResp.StatusCode, ucResp.Message)
+			return
+		}
+
+		c.Set("apiClient", client)
+		c.Set("actor", client.Name)
+		c.Set("tenantId", client.MerchantID)
+		c.Next()
+	}
+}
+
+// RequireScope only lets API clients granted the scope through. It must run after APIKeyMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, ok := c.Value("apiClient").(*entity.APIClient)
+		if !ok {
+			abort(c, http.StatusUnauthorized, "API key is required")
+			return
+		}
+		if !client.HasScope(scope) {
+			abort(c, http.StatusForbidden, "API client is not allowed to use the '"+scope+"' scope")
+			return
+		}
+		c.Next()
+	}
+}
+
+func abort(c *gin.Context, code int, message string) {
+	c.AbortWithStatusJSON(code, model.Response{
+		Status:  "failed",
+		Code:    code,
+		Message: message,
+	})
+}
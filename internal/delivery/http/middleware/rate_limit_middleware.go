@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/usecase"
+	"strconv"
+)
+
+// This Middleware limits the requests of the API client to a route group and reports the limit closest to being
+// reached in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers. It must run after APIKeyMiddleware.
+
+func RateLimitMiddleware(rateLimiter *usecase.RateLimiter) func(group string) gin.HandlerFunc {
+	return func(group string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			client, ok := c.Value("apiClient").(*entity.APIClient)
+			if !ok {
+				abort(c, http.StatusUnauthorized, "API key is required")
+				return
+			}
+
+			ucResp, decision := rateLimiter.Allow(c, client, group)
+			if decision == nil {
+				c.Next()
+				return
+			}
+
+			reset := strconv.Itoa(int(math.Ceil(decision.Reset.Seconds())))
+			c.Header("RateLimit-Policy", decision.Policy)
+			c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			c.Header("RateLimit-Reset", reset)
+			if !decision.Allowed {
+				c.Header("Retry-After", reset)
+				abort(c, ucResp.StatusCode, ucResp.Message)
+				return
+			}
+			c.Next()
+		}
+	}
+}
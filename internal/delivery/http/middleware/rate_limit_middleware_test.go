@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/usecase"
+	"testing"
+	"time"
+)
+
+// scriptReply answers every script run with the same reply, standing in for the sliding window script
+type scriptReply []interface{}
+
+func (r scriptReply) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("fake redis does not dial")
+	}
+}
+
+func (r scriptReply) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		cmd.(*redis.Cmd).SetVal([]interface{}(r))
+		return nil
+	}
+}
+
+func (r scriptReply) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		reply  scriptReply
+		status int
+		want   map[string]string
+	}{
+		{
+			name:   "allowed",
+			reply:  scriptReply{int64(1), int64(3), int64(59000)},
+			status: http.StatusOK,
+			want: map[string]string{"RateLimit-Policy": "10;w=60", "RateLimit-Limit": "10", "RateLimit-Remaining": "7",
+				"RateLimit-Reset": "59", "Retry-After": ""},
+		},
+		{
+			name:   "refused",
+			reply:  scriptReply{int64(0), int64(10), int64(30500)},
+			status: http.StatusTooManyRequests,
+			want: map[string]string{"RateLimit-Policy": "10;w=60", "RateLimit-Limit": "10", "RateLimit-Remaining": "0",
+				"RateLimit-Reset": "31", "Retry-After": "31"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logrus.New()
+			log.SetOutput(io.Discard)
+			rds := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+			rds.AddHook(tt.reply)
+			limiter := usecase.NewRateLimiter(rds, log, usecase.RateLimitConfig{Window: time.Minute, DefaultLimit: 10},
+				usecase.NewSettings(usecase.RuntimeSettings{RateLimitEnabled: true}))
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("apiClient", &entity.APIClient{ID: "client-1"})
+			})
+			router.GET("/rates", RateLimitMiddleware(limiter)("rates"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/rates", nil))
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			for header, want := range tt.want {
+				if got := recorder.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddlewareRequiresClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/rates", RateLimitMiddleware(&usecase.RateLimiter{})("rates"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/rates", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type PackingController struct {
+	Log            *logrus.Logger
+	PackingUseCase *usecase.PackingUseCase
+}
+
+func NewPackingController(log *logrus.Logger, packingUseCase *usecase.PackingUseCase) *PackingController {
+	return &PackingController{
+		Log:            log,
+		PackingUseCase: packingUseCase,
+	}
+}
+
+func (pc *PackingController) Pack(c *gin.Context) {
+	log := pc.Log.WithField("traceId", c.Value("traceId"))
+
+	var req model.PackingRequest
+	if err := validator.ValidatePackingRequest(c, &req); err != nil {
+		log.Errorf("Invalid request parameters, error: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Message: err.Error(), Status: "failed"})
+		return
+	}
+
+	ucResp, resp := pc.PackingUseCase.Pack(c, req.PackingOptions, req.Items)
+	if ucResp.StatusCode != http.StatusOK {
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.PackingResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type QuoteController struct {
+	Log          *logrus.Logger
+	QuoteUseCase *usecase.QuoteUseCase
+}
+
+func NewQuoteController(log *logrus.Logger, quoteUseCase *usecase.QuoteUseCase) *QuoteController {
+	return &QuoteController{
+		Log:          log,
+		QuoteUseCase: quoteUseCase,
+	}
+}
+
+func (qc *QuoteController) GetQuote(c *gin.Context) {
+	log := qc.Log.WithField("traceId", c.Value("traceId"))
+
+	id := c.Param("id")
+	ucResp, resp := qc.QuoteUseCase.GetQuote(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		log.Errorf("Error getting quote: %s, error: %s", id, ucResp.Message)
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+		})
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.QuoteResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+		},
+		Data: *resp,
+	})
+}
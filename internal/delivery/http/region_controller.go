@@ -0,0 +1,103 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"shipping-gateway/internal/delivery/http/validator"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/usecase"
+)
+
+type RegionController struct {
+	Log           *logrus.Logger
+	RegionUseCase *usecase.RegionUseCase
+}
+
+func NewRegionController(log *logrus.Logger, regionUseCase *usecase.RegionUseCase) *RegionController {
+	return &RegionController{
+		Log:           log,
+		RegionUseCase: regionUseCase,
+	}
+}
+
+func (rc *RegionController) ListProvinces(c *gin.Context) {
+	ucResp, resp := rc.RegionUseCase.ListProvinces(c)
+	if ucResp.StatusCode != http.StatusOK {
+		rc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.ProvinceListResp{Response: rc.success(ucResp), Data: resp})
+}
+
+func (rc *RegionController) ListCities(c *gin.Context) {
+	id, ok := rc.regionID(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := rc.RegionUseCase.ListCities(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		rc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.CityListResp{Response: rc.success(ucResp), Data: resp})
+}
+
+func (rc *RegionController) ListDistricts(c *gin.Context) {
+	id, ok := rc.regionID(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := rc.RegionUseCase.ListDistricts(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		rc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.DistrictListResp{Response: rc.success(ucResp), Data: resp})
+}
+
+func (rc *RegionController) ListSubdistricts(c *gin.Context) {
+	id, ok := rc.regionID(c)
+	if !ok {
+		return
+	}
+
+	ucResp, resp := rc.RegionUseCase.ListSubdistricts(c, id)
+	if ucResp.StatusCode != http.StatusOK {
+		rc.abort(c, ucResp)
+		return
+	}
+
+	c.JSON(ucResp.StatusCode, model.SubdistrictListResp{Response: rc.success(ucResp), Data: resp})
+}
+
+func (rc *RegionController) regionID(c *gin.Context) (uint, bool) {
+	id, err := validator.ValidateRegionID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{Code: http.StatusBadRequest, Message: err.Error(), Status: "failed"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (rc *RegionController) success(ucResp *model.ServiceResponse) model.Response {
+	return model.Response{
+		Status:  "success",
+		Code:    ucResp.StatusCode,
+		Message: "success",
+	}
+}
+
+func (rc *RegionController) abort(c *gin.Context, ucResp *model.ServiceResponse) {
+	rc.Log.WithField("traceId", c.Value("traceId")).Errorf("Error listing regions: %s", ucResp.Message)
+	c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+		Status:  "failed",
+		Code:    ucResp.StatusCode,
+		Message: ucResp.Message,
+	})
+}
@@ -3,6 +3,8 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"shipping-gateway/internal/delivery/http"
+	"shipping-gateway/internal/delivery/http/middleware"
+	"shipping-gateway/internal/entity"
 )
 
 type RouteConfig struct {
@@ -12,14 +14,26 @@ type RouteConfig struct {
 	HealthCheckController *http.HealthCheckController
 	CourierRateController *http.CourierRateController
 	TrackingController    *http.TrackingController
+	QuoteController       *http.QuoteController
+	BulkRateController    *http.BulkRateController
+	PackingController     *http.PackingController
+	AreaAdminController   *http.AreaAdminController
+	RegionController      *http.RegionController
+	AddressController     *http.AddressController
+	APIClientController   *http.APIClientController
+	MerchantController    *http.MerchantController
 
 	// Add middleware below
 	TraceIDMiddleware gin.HandlerFunc
+	APIKeyMiddleware  gin.HandlerFunc
+
+	RateLimitMiddleware func(group string) gin.HandlerFunc
 }
 
 func (c *RouteConfig) Setup() {
 	c.SetupGuestRoute()
 	c.SetupInternalRoute()
+	c.SetupAdminRoute()
 }
 
 // SetupGuestRoute is used to setup routes that can be accessed by guest users without authentication.
@@ -33,15 +47,77 @@ func (c *RouteConfig) SetupGuestRoute() {
 }
 
 // SetupInternalRoute is used to setup routes that can be accessed by internal users with authentication.
-// This function should be called to define routes that require authentication and are intended for internal use only
+// This function should be called to define routes that require authentication and are intended for internal use only.
+// Every group requires an API key granted the scope of the group, and is rate limited per client
 func (c *RouteConfig) SetupInternalRoute() {
 	v1 := c.App.Group("/api/v1")
-	v1.Use(c.TraceIDMiddleware)
+	v1.Use(c.TraceIDMiddleware, c.APIKeyMiddleware)
 	// Shipping routes
-	shippingV1 := v1.Group("/shipping")
+	shippingV1 := v1.Group("/shipping", c.scoped(entity.ScopeRates, "shipping")...)
 	shippingV1.POST("/rates", c.CourierRateController.GetCourierRates)
+	shippingV1.POST("/rates/batch", c.CourierRateController.GetBatchCourierRates)
+	shippingV1.POST("/rates/bulk", c.BulkRateController.Upload)
+	shippingV1.GET("/rates/bulk/:id", c.BulkRateController.GetJob)
+	shippingV1.GET("/rates/bulk/:id/result", c.BulkRateController.DownloadResult)
+
+	// Packing routes
+	packingV1 := v1.Group("/packing", c.scoped(entity.ScopeRates, "packing")...)
+	packingV1.POST("", c.PackingController.Pack)
 
 	// Tracking routes
-	trackingV1 := v1.Group("/tracking")
+	trackingV1 := v1.Group("/tracking", c.scoped(entity.ScopeTracking, "tracking")...)
+	trackingV1.GET("/:waybill", c.TrackingController.DetectTracking)
 	trackingV1.GET("/:waybill/courier/:courier", c.TrackingController.GetTrackingByWaybill)
+
+	// Quote routes
+	quoteV1 := v1.Group("/quotes", c.scoped(entity.ScopeShipments, "quotes")...)
+	quoteV1.GET("/:id", c.QuoteController.GetQuote)
+
+	// Region routes
+	regionV1 := v1.Group("/regions", c.scoped(entity.ScopeRates, "regions")...)
+	regionV1.GET("/provinces", c.RegionController.ListProvinces)
+	regionV1.GET("/provinces/:id/cities", c.RegionController.ListCities)
+	regionV1.GET("/cities/:id/districts", c.RegionController.ListDistricts)
+	regionV1.GET("/districts/:id/subdistricts", c.RegionController.ListSubdistricts)
+
+	// Address routes
+	addressV1 := v1.Group("/addresses", c.scoped(entity.ScopeRates, "addresses")...)
+	addressV1.POST("/parse", c.AddressController.Parse)
+}
+
+// SetupAdminRoute is used to setup routes that can only be accessed by API clients granted the admin scope.
+// These routes change reference data used by every request, such as the area mappings, the API clients and
+// the merchants
+func (c *RouteConfig) SetupAdminRoute() {
+	adminV1 := c.App.Group("/api/v1/admin")
+	adminV1.Use(c.TraceIDMiddleware, c.APIKeyMiddleware, middleware.RequireScope(entity.ScopeAdmin))
+
+	// Area mapping routes
+	areaV1 := adminV1.Group("/areas")
+	areaV1.GET("", c.AreaAdminController.ListAreas)
+	areaV1.POST("", c.AreaAdminController.CreateArea)
+	areaV1.GET("/:id", c.AreaAdminController.GetArea)
+	areaV1.PUT("/:id", c.AreaAdminController.OverrideArea)
+	areaV1.DELETE("/:id", c.AreaAdminController.DeleteArea)
+	areaV1.POST("/:id/re-resolve", c.AreaAdminController.ReResolveArea)
+	areaV1.GET("/:id/audits", c.AreaAdminController.GetAreaAudits)
+
+	// API client routes
+	clientV1 := adminV1.Group("/api-clients")
+	clientV1.GET("", c.APIClientController.ListClients)
+	clientV1.POST("", c.APIClientController.CreateClient)
+	clientV1.PATCH("/:id", c.APIClientController.UpdateClient)
+	clientV1.POST("/:id/rotate", c.APIClientController.RotateKey)
+
+	// Merchant routes
+	merchantV1 := adminV1.Group("/merchants")
+	merchantV1.GET("", c.MerchantController.ListMerchants)
+	merchantV1.POST("", c.MerchantController.CreateMerchant)
+	merchantV1.GET("/:id", c.MerchantController.GetMerchant)
+	merchantV1.PATCH("/:id", c.MerchantController.UpdateMerchant)
+}
+
+// scoped returns the middlewares of an internal route group: the scope check followed by the rate limit of the group
+func (c *RouteConfig) scoped(scope, group string) []gin.HandlerFunc {
+	return []gin.HandlerFunc{middleware.RequireScope(scope), c.RateLimitMiddleware(group)}
 }
@@ -47,3 +47,32 @@ func (tc *TrackingController) GetTrackingByWaybill(c *gin.Context) {
 
 	c.JSON(ucResp.StatusCode, response)
 }
+
+func (tc *TrackingController) DetectTracking(c *gin.Context) {
+	log := tc.Log.WithField("traceId", c.Value("traceId"))
+
+	waybill := c.Param("waybill")
+	ucResp, resp, candidates := tc.TrackingUseCase.DetectTracking(c, waybill)
+	if ucResp.StatusCode != 200 {
+		log.Errorf("Error detecting courier of waybill: %s, error: %s", waybill, ucResp.Message)
+		c.AbortWithStatusJSON(ucResp.StatusCode, model.Response{
+			Status:  "failed",
+			Code:    ucResp.StatusCode,
+			Message: ucResp.Message,
+			Meta:    model.CourierDetection{Candidates: candidates},
+		})
+		return
+	}
+
+	response := model.ShipmentTrackingResp{
+		Response: model.Response{
+			Status:  "success",
+			Code:    ucResp.StatusCode,
+			Message: "success",
+			Meta:    model.CourierDetection{CourierCode: resp.CourierCode, Candidates: candidates},
+		},
+		Data: *resp,
+	}
+
+	c.JSON(ucResp.StatusCode, response)
+}
@@ -0,0 +1,26 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"shipping-gateway/internal/model"
+	"strings"
+)
+
+// maxAddressLength limits the size of an address to parse
+const maxAddressLength = 1000
+
+func ValidateAddressParseRequest(c *gin.Context, req *model.AddressParseRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if strings.TrimSpace(req.Address) == "" {
+		return fmt.Errorf("invalid request : field 'address' is required")
+	}
+	if len(req.Address) > maxAddressLength {
+		return fmt.Errorf("invalid request : field 'address' must not be longer than %d characters", maxAddressLength)
+	}
+
+	return nil
+}
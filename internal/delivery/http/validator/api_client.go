@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"slices"
+	"strings"
+)
+
+func ValidateAPIClientCreateRequest(c *gin.Context, req *model.APIClientCreateRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("invalid request : field 'name' is required")
+	}
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("invalid request : field 'scopes' must contain at least one scope")
+	}
+	if err := validateLimits(&req.RateLimit, &req.MonthlyQuota); err != nil {
+		return err
+	}
+	// admin routes are not scoped to a merchant, so a merchant client must not reach them
+	if req.MerchantID != "" && slices.Contains(req.Scopes, entity.ScopeAdmin) {
+		return fmt.Errorf("invalid request : scope '%s' cannot be granted to a merchant client", entity.ScopeAdmin)
+	}
+
+	return validateScopes(req.Scopes)
+}
+
+func ValidateAPIClientUpdateRequest(c *gin.Context, req *model.APIClientUpdateRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if req.Scopes == nil && req.Enabled == nil && req.RateLimit == nil && req.MonthlyQuota == nil {
+		return fmt.Errorf("invalid request : one of fields 'scopes', 'enabled', 'rate_limit' or 'monthly_quota' must be provided")
+	}
+	if req.Scopes != nil && len(req.Scopes) == 0 {
+		return fmt.Errorf("invalid request : field 'scopes' must contain at least one scope")
+	}
+	if err := validateLimits(req.RateLimit, req.MonthlyQuota); err != nil {
+		return err
+	}
+
+	return validateScopes(req.Scopes)
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !slices.Contains(entity.Scopes, scope) {
+			return fmt.Errorf("invalid request : scope '%s' must be one of %s", scope, strings.Join(entity.Scopes, ", "))
+		}
+	}
+	return nil
+}
+
+func validateLimits(rateLimit, monthlyQuota *int) error {
+	if rateLimit != nil && *rateLimit < 0 {
+		return fmt.Errorf("invalid request : field 'rate_limit' must not be negative")
+	}
+	if monthlyQuota != nil && *monthlyQuota < -1 {
+		return fmt.Errorf("invalid request : field 'monthly_quota' must be -1 for unlimited or more")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"shipping-gateway/internal/model"
+	"strconv"
+)
+
+// maxAreaPageSize limits how many areas can be listed at once
+const maxAreaPageSize = 100
+
+func ValidateAreaID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid request : area id must be a positive number")
+	}
+	return uint(id), nil
+}
+
+func ValidateAreaFilter(c *gin.Context, filter *model.AreaFilter) error {
+	if err := c.ShouldBindQuery(filter); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Size == 0 {
+		filter.Size = 20
+	}
+	if filter.Page < 0 {
+		return fmt.Errorf("invalid request : field 'page' must be positive")
+	}
+	if filter.Size < 0 || filter.Size > maxAreaPageSize {
+		return fmt.Errorf("invalid request : field 'size' must be between 1 and %d", maxAreaPageSize)
+	}
+
+	return nil
+}
+
+func ValidateAreaCreateRequest(c *gin.Context, req *model.AreaCreateRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if req.OriginalSubdistrictID == 0 {
+		return fmt.Errorf("invalid request : field 'original_subdistrict_id' is required")
+	}
+	if req.ExternalID == "" {
+		return fmt.Errorf("invalid request : field 'external_id' is required")
+	}
+	if req.Reason == "" {
+		return fmt.Errorf("invalid request : field 'reason' is required")
+	}
+
+	return validateAreaCoordinate(req.Latitude, req.Longitude)
+}
+
+func ValidateAreaOverrideRequest(c *gin.Context, req *model.AreaOverrideRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if req.ExternalID == "" {
+		return fmt.Errorf("invalid request : field 'external_id' is required")
+	}
+	if req.Reason == "" {
+		return fmt.Errorf("invalid request : field 'reason' is required")
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return fmt.Errorf("invalid request : fields 'latitude' and 'longitude' must be provided together")
+	}
+	if req.Latitude != nil {
+		return validateAreaCoordinate(*req.Latitude, *req.Longitude)
+	}
+
+	return nil
+}
+
+func ValidateAreaChangeRequest(c *gin.Context, req *model.AreaChangeRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if req.Reason == "" {
+		return fmt.Errorf("invalid request : field 'reason' is required")
+	}
+
+	return nil
+}
+
+func validateAreaCoordinate(latitude, longitude float64) error {
+	if (latitude == 0) != (longitude == 0) {
+		return fmt.Errorf("invalid request : fields 'latitude' and 'longitude' must be provided together")
+	}
+
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("invalid request : fields 'latitude' and 'longitude' must be valid coordinates")
+	}
+
+	return nil
+}
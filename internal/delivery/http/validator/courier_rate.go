@@ -11,17 +11,95 @@ func ValidateCourierRateRequest(c *gin.Context, req *model.CourierRateRequest) e
 		return fmt.Errorf("invalid request format: %w", err)
 	}
 
-	if req.OriginSubdistrictID == "" && req.OriginPostalCode == "" && req.OriginQuery == "" {
-		return fmt.Errorf("invalid request : field 'origin_subdistrict_id', 'origin_postal_code', or 'origin_query' must be provided")
+	return validateCourierRateFields(req, hasMerchantDefaults(c))
+}
+
+func ValidateBatchRateRequest(c *gin.Context, req *model.BatchRateRequest, maxLegs int) error {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if len(req.Legs) == 0 {
+		return fmt.Errorf("invalid request : field 'legs' must contain at least one leg")
+	}
+
+	if len(req.Legs) > maxLegs {
+		return fmt.Errorf("invalid request : field 'legs' must not contain more than %d legs", maxLegs)
 	}
 
-	if req.DestinationSubdistrictID == "" && req.DestinationPostalCode == "" && req.DestinationQuery == "" {
-		return fmt.Errorf("invalid request : field 'destination_subdistrict_id', 'destination_postal_code', or 'destination_query' must be provided")
+	for i := range req.Legs {
+		if err := validateCourierRateFields(&req.Legs[i], hasMerchantDefaults(c)); err != nil {
+			return fmt.Errorf("legs[%d]: %w", i, err)
+		}
 	}
 
-	if req.CourierCode == "" {
+	return nil
+}
+
+// hasMerchantDefaults reports whether the request is made for a merchant, whose default origin and couriers fill
+// the fields left empty
+func hasMerchantDefaults(c *gin.Context) bool {
+	return c.GetString("tenantId") != ""
+}
+
+func validateCourierRateFields(req *model.CourierRateRequest, merchantDefaults bool) error {
+	if !merchantDefaults && req.OriginSubdistrictID == "" && req.OriginPostalCode == "" && req.OriginQuery == "" && req.OriginAddress == "" {
+		return fmt.Errorf("invalid request : field 'origin_subdistrict_id', 'origin_postal_code', 'origin_query', or 'origin_address' must be provided")
+	}
+
+	if req.DestinationSubdistrictID == "" && req.DestinationPostalCode == "" && req.DestinationQuery == "" && req.DestinationAddress == "" {
+		return fmt.Errorf("invalid request : field 'destination_subdistrict_id', 'destination_postal_code', 'destination_query', or 'destination_address' must be provided")
+	}
+
+	if !merchantDefaults && req.CourierCode == "" {
 		return fmt.Errorf("invalid request : field 'courier_code' must be provided")
 	}
 
+	switch req.SortBy {
+	case "", model.RateSortByPrice, model.RateSortByETD:
+	default:
+		return fmt.Errorf("invalid request : field 'sort_by' must be one of 'price' or 'etd'")
+	}
+
+	if err := validateCoordinate("origin", req.OriginLatitude, req.OriginLongitude); err != nil {
+		return err
+	}
+
+	if err := validateCoordinate("destination", req.DestinationLatitude, req.DestinationLongitude); err != nil {
+		return err
+	}
+
+	if req.Packing != nil {
+		if err := validatePackingOptions(*req.Packing); err != nil {
+			return err
+		}
+		if err := validatePackingQuantities(req.Items); err != nil {
+			return err
+		}
+	}
+
+	if req.DeclaredValue < 0 {
+		return fmt.Errorf("invalid request : field 'declared_value' must not be negative")
+	}
+
+	if req.Filter.MaxPrice < 0 {
+		return fmt.Errorf("invalid request : field 'filter.max_price' must not be negative")
+	}
+
+	return nil
+}
+
+func validateCoordinate(prefix string, latitude, longitude *float64) error {
+	if (latitude == nil) != (longitude == nil) {
+		return fmt.Errorf("invalid request : fields '%s_latitude' and '%s_longitude' must be provided together", prefix, prefix)
+	}
+	if latitude == nil {
+		return nil
+	}
+
+	if *latitude < -90 || *latitude > 90 || *longitude < -180 || *longitude > 180 {
+		return fmt.Errorf("invalid request : fields '%s_latitude' and '%s_longitude' must be valid coordinates", prefix, prefix)
+	}
+
 	return nil
 }
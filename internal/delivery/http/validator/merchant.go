@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"shipping-gateway/internal/model"
+	"strconv"
+	"strings"
+)
+
+func ValidateMerchantCreateRequest(c *gin.Context, req *model.MerchantCreateRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("invalid request : field 'name' is required")
+	}
+	if strings.TrimSpace(req.Credentials.BiteshipAPIKey) == "" {
+		return fmt.Errorf("invalid request : field 'credentials.biteship_api_key' is required")
+	}
+
+	return validatePostalCode("default_origin_postal_code", req.DefaultOriginPostalCode)
+}
+
+func ValidateMerchantUpdateRequest(c *gin.Context, req *model.MerchantUpdateRequest) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return fmt.Errorf("invalid request : field 'name' must not be empty")
+	}
+	if req.Credentials != nil && strings.TrimSpace(req.Credentials.BiteshipAPIKey) == "" {
+		return fmt.Errorf("invalid request : field 'credentials.biteship_api_key' is required")
+	}
+	if req.DefaultOriginPostalCode != nil {
+		return validatePostalCode("default_origin_postal_code", *req.DefaultOriginPostalCode)
+	}
+
+	return nil
+}
+
+func validatePostalCode(field, postalCode string) error {
+	if postalCode == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(postalCode); err != nil || len(postalCode) != 5 {
+		return fmt.Errorf("invalid request : field '%s' must be a 5 digit postal code", field)
+	}
+	return nil
+}
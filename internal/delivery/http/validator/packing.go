@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"shipping-gateway/internal/model"
+)
+
+func ValidatePackingRequest(c *gin.Context, req *model.PackingRequest) error {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return fmt.Errorf("invalid request format: %w", err)
+	}
+
+	if len(req.Items) == 0 {
+		return fmt.Errorf("invalid request : field 'items' must contain at least one item")
+	}
+
+	for i, item := range req.Items {
+		if item.Length <= 0 || item.Width <= 0 || item.Height <= 0 || item.Weight <= 0 {
+			return fmt.Errorf("invalid request : items[%d] must have positive length, width, height and weight", i)
+		}
+		if item.Quantity < 0 {
+			return fmt.Errorf("invalid request : items[%d] quantity must not be negative", i)
+		}
+	}
+
+	if err := validatePackingQuantities(req.Items); err != nil {
+		return err
+	}
+
+	return validatePackingOptions(req.PackingOptions)
+}
+
+// validatePackingQuantities bounds the units to pack, as every unit is placed on its own
+func validatePackingQuantities(items []model.ItemRequest) error {
+	units := 0
+	for i, item := range items {
+		if item.Quantity > model.MaxPackingQuantity {
+			return fmt.Errorf("invalid request : items[%d] quantity must not be more than %d when packing", i, model.MaxPackingQuantity)
+		}
+		units += max(item.Quantity, 1)
+	}
+	if units > model.MaxPackingUnits {
+		return fmt.Errorf("invalid request : items must not contain more than %d units when packing", model.MaxPackingUnits)
+	}
+	return nil
+}
+
+func validatePackingOptions(options model.PackingOptions) error {
+	switch options.Strategy {
+	case "", model.PackingStrategyFewest, model.PackingStrategyCheapest:
+		return nil
+	default:
+		return fmt.Errorf("invalid request : field 'strategy' must be one of 'fewest' or 'cheapest'")
+	}
+}
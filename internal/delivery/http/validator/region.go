@@ -0,0 +1,15 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+func ValidateRegionID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid request : region id must be a positive number")
+	}
+	return uint(id), nil
+}
@@ -7,12 +7,16 @@ import (
 
 type Area struct {
 	ID                    uint      `json:"id" gorm:"primaryKey"`
-	OriginalSubdistrictID uint      `json:"original_subdistrict_id" gorm:"not null"`               // ID from the original subdistrict
+	OriginalSubdistrictID uint      `json:"original_subdistrict_id" gorm:"not null;index"`         // ID from the original subdistrict
 	OriginalPostalCode    string    `json:"original_postal_code" gorm:"type:varchar(20);not null"` // Postal code from the original subdistrict
 	Description           string    `json:"description" gorm:"not null"`
 	ExternalSource        string    `json:"external_source" gorm:"type:varchar(100);not null"` // Name of the external service providing the area data
-	ExternalID            string    `json:"external_id" gorm:"type:varchar(200);unique"`       // ID from the external service
+	ExternalID            string    `json:"external_id" gorm:"type:varchar(200);index"`        // ID from the external service, shared by subdistricts of the same provider area
 	ExternalInfo          string    `json:"external_info" gorm:"type:text"`                    // JSON string containing additional info from the external service
+	Latitude              float64   `json:"latitude" gorm:"type:decimal(10,7);default:0"`      // Latitude of the area, 0 when unknown
+	Longitude             float64   `json:"longitude" gorm:"type:decimal(10,7);default:0"`     // Longitude of the area, 0 when unknown
+	MatchScore            float64   `json:"match_score" gorm:"type:decimal(5,4);default:0"`    // Confidence of the mapping to the external area (0-1), 0 when not scored
+	NeedsReview           bool      `json:"needs_review" gorm:"not null;default:false;index"`  // Mapping confidence is too low and should be checked manually
 	CreatedAt             time.Time `json:"created_at" gorm:"autoCreateTime"`                  // Timestamp when the record was created
 	UpdatedAt             time.Time `json:"updated_at" gorm:"autoUpdateTime"`                  // Timestamp
 }
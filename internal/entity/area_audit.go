@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Area audit actions
+const (
+	AreaAuditCreate    = "create"
+	AreaAuditOverride  = "override"
+	AreaAuditReResolve = "re_resolve"
+	AreaAuditDelete    = "delete"
+)
+
+type AreaAudit struct {
+	ID        uint      `gorm:"primaryKey"`
+	AreaID    uint      `gorm:"not null;index"`             // ID of the changed area
+	Action    string    `gorm:"type:varchar(20);not null"`  // What was done to the area
+	Actor     string    `gorm:"type:varchar(100);not null"` // Who made the change
+	Reason    string    `gorm:"type:varchar(255)"`          // Why the change was made
+	Before    string    `gorm:"type:text"`                  // JSON string of the area before the change, empty on create
+	After     string    `gorm:"type:text"`                  // JSON string of the area after the change, empty on delete
+	CreatedAt time.Time `gorm:"autoCreateTime"`             // Timestamp when the change was made
+}
+
+// TableName returns the name of the table in the database
+func (a *AreaAudit) TableName() string {
+	return "area_audits"
+}
+
+// BeforeCreate is a GORM hook that sets the CreatedAt timestamp
+func (a *AreaAudit) BeforeCreate(tx *gorm.DB) error {
+	a.CreatedAt = time.Now()
+	return nil
+}
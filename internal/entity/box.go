@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Box struct {
+	ID         uint           `gorm:"primaryKey"`
+	MerchantID string         `gorm:"type:varchar(50);not null;default:'';index"` // Merchant owning the box, empty for the default set
+	Name       string         `gorm:"type:varchar(100);not null"`                 // Name of the box, e.g. "Small"
+	Length     int            `gorm:"not null"`                                   // Inner length in centimeters
+	Width      int            `gorm:"not null"`                                   // Inner width in centimeters
+	Height     int            `gorm:"not null"`                                   // Inner height in centimeters
+	MaxWeight  int            `gorm:"not null;default:0"`                         // Maximum content weight in grams, 0 for no limit
+	BoxWeight  int            `gorm:"not null;default:0"`                         // Weight of the empty box in grams
+	Cost       int            `gorm:"not null;default:0"`                         // Cost of the box
+	Enabled    bool           `gorm:"not null;default:true"`                      // Disabled boxes are never used
+	CreatedAt  time.Time      `gorm:"autoCreateTime"`                             // Timestamp when the record was created
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime"`                             // Timestamp
+	DeletedAt  gorm.DeletedAt `gorm:"index"`                                      // Soft delete field
+}
+
+// TableName returns the name of the table in the database
+func (Box) TableName() string {
+	return "boxes"
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type BulkRateJobStatus string
+
+const (
+	BulkRateJobPending    BulkRateJobStatus = "pending"
+	BulkRateJobProcessing BulkRateJobStatus = "processing"
+	BulkRateJobCompleted  BulkRateJobStatus = "completed"
+	BulkRateJobFailed     BulkRateJobStatus = "failed"
+)
+
+type BulkRateJob struct {
+	ID            string            `gorm:"primaryKey;type:varchar(36)"`
+	MerchantID    string            `gorm:"type:varchar(36);index"`          // Merchant that uploaded the file, empty for the platform
+	FileName      string            `gorm:"type:varchar(255);not null"`      // Name of the uploaded file
+	Status        BulkRateJobStatus `gorm:"type:varchar(20);not null;index"` // Processing status of the job
+	TotalRows     int               `gorm:"not null;default:0"`              // Number of routes in the upload
+	ProcessedRows int               `gorm:"not null;default:0"`              // Number of routes priced so far
+	FailedRows    int               `gorm:"not null;default:0"`              // Number of routes that could not be priced
+	InputPath     string            `gorm:"type:varchar(255);not null"`      // Location of the uploaded CSV
+	ResultPath    string            `gorm:"type:varchar(255)"`               // Location of the result CSV
+	Error         string            `gorm:"type:text"`                       // Reason the job failed
+	StartedAt     *time.Time        // Timestamp when processing started
+	FinishedAt    *time.Time        // Timestamp when processing finished
+	CreatedAt     time.Time         `gorm:"autoCreateTime"` // Timestamp when the record was created
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime"` // Timestamp
+}
+
+// TableName returns the name of the table in the database
+func (j *BulkRateJob) TableName() string {
+	return "bulk_rate_jobs"
+}
+
+// BeforeCreate is a GORM hook that sets the ID when it has not been assigned yet
+func (j *BulkRateJob) BeforeCreate(tx *gorm.DB) (err error) {
+	if j.ID == "" {
+		id, _ := uuid.NewV7()
+		j.ID = id.String()
+	}
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+	return nil
+}
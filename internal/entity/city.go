@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type City struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement:false"`   // Official city ID from the master dataset
+	ProvinceID uint      `gorm:"not null;index"`                   // ID of the province the city belongs to
+	Type       string    `gorm:"type:varchar(20)"`                 // Kota or Kabupaten
+	Name       string    `gorm:"type:varchar(100);not null;index"` // Name of the city without its type
+	CreatedAt  time.Time `gorm:"autoCreateTime"`                   // Timestamp when the record was created
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`                   // Timestamp
+
+	Province Province `gorm:"foreignKey:ProvinceID"`
+}
+
+// TableName returns the name of the table in the database
+func (City) TableName() string {
+	return "cities"
+}
@@ -7,11 +7,13 @@ import (
 
 type Courier struct {
 	ID        uint           `gorm:"primaryKey"`
-	Code      string         `gorm:"type:varchar(50);not null;unique"` // Unique code for the courier
+	Code      string         `gorm:"type:varchar(50);not null;unique"` // Unique canonical code for the courier, used in requests, cache keys and stored records
 	Name      string         `gorm:"type:varchar(100);not null"`       // Name of the courier
 	CreatedAt time.Time      `gorm:"autoCreateTime"`                   // Timestamp when the record was created
 	UpdatedAt time.Time      `gorm:"autoUpdateTime"`                   // Timestamp
 	DeletedAt gorm.DeletedAt `gorm:"index"`                            // Soft delete field
+
+	Aliases []CourierAlias `gorm:"foreignKey:CourierID"`
 }
 
 // TableName returns the name of the table in the database
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type CourierAlias struct {
+	ID        uint      `gorm:"primaryKey"`
+	CourierID uint      `gorm:"not null;index"`                                                      // ID of the courier the alias stands for
+	Provider  string    `gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_provider_alias"` // Provider using the alias, empty for aliases sent by clients
+	Alias     string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_provider_alias"`           // Lowercase alternative code or name of the courier
+	IsPrimary bool      `gorm:"not null;default:false"`                                              // The alias is the code sent to the provider for this courier
+	CreatedAt time.Time `gorm:"autoCreateTime"`                                                      // Timestamp when the record was created
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`                                                      // Timestamp
+}
+
+// TableName returns the name of the table in the database
+func (CourierAlias) TableName() string {
+	return "courier_aliases"
+}
@@ -13,6 +13,7 @@ type CourierService struct {
 	Description string    `gorm:"type:varchar(200)"`               // Description of the courier service
 	ServiceType string    `gorm:"type:varchar(50);not null;index"` // Type of the courier service (e.g., delivery, pickup)
 	ETD         string    `gorm:"type:varchar(50);not null"`       // Estimated Time of Delivery for the service
+	OnTimeRate  float64   `gorm:"type:decimal(5,4);default:0"`     // Historical share of shipments delivered within ETD (0-1), 0 when unknown
 	CreatedAt   time.Time `gorm:"autoCreateTime"`                  // Timestamp when the record was created
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`                  // Timestamp
 }
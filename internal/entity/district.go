@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type District struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:false"`   // Official district (kecamatan) ID from the master dataset
+	CityID    uint      `gorm:"not null;index"`                   // ID of the city the district belongs to
+	Name      string    `gorm:"type:varchar(100);not null;index"` // Name of the district
+	CreatedAt time.Time `gorm:"autoCreateTime"`                   // Timestamp when the record was created
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`                   // Timestamp
+
+	City City `gorm:"foreignKey:CityID"`
+}
+
+// TableName returns the name of the table in the database
+func (District) TableName() string {
+	return "districts"
+}
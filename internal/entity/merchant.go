@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Merchant is a tenant of the gateway shipping with its own provider accounts. API clients linked to a merchant
+// only see the quotes, bulk jobs and tracking logs of that merchant, and are priced with its pricing rules.
+type Merchant struct {
+	ID                      string    `gorm:"primaryKey;type:varchar(36)"`
+	Name                    string    `gorm:"type:varchar(100);not null"`
+	Enabled                 bool      `gorm:"not null;default:true"` // Requests of a disabled merchant are refused
+	Credentials             string    `gorm:"type:text;not null"`    // Encrypted JSON of the provider credentials
+	DefaultCouriers         string    `gorm:"type:varchar(255)"`     // Couriers quoted when a request does not name any
+	DefaultOriginPostalCode string    `gorm:"type:varchar(20)"`      // Origin used when a request does not give one
+	CreatedAt               time.Time `gorm:"autoCreateTime"`        // Timestamp when the record was created
+	UpdatedAt               time.Time `gorm:"autoUpdateTime"`        // Timestamp
+}
+
+// TableName returns the name of the table in the database
+func (m *Merchant) TableName() string {
+	return "merchants"
+}
+
+// BeforeCreate is a GORM hook that sets the ID when it has not been assigned yet
+func (m *Merchant) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		id, _ := uuid.NewV7()
+		m.ID = id.String()
+	}
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type PricingRuleAction string
+
+const (
+	PricingRuleActionMarkupPercent PricingRuleAction = "markup_percent" // Adjust the price by a percentage, negative for discounts
+	PricingRuleActionFixedFee      PricingRuleAction = "fixed_fee"      // Add a fixed amount, negative for discounts
+	PricingRuleActionCap           PricingRuleAction = "cap"            // Limit the price to a maximum amount
+	PricingRuleActionFree          PricingRuleAction = "free"           // Make the shipping free
+)
+
+type PricingRule struct {
+	ID                  uint              `gorm:"primaryKey"`
+	MerchantID          string            `gorm:"type:varchar(36);index"`                // Merchant the rule prices for, empty for the platform
+	Name                string            `gorm:"type:varchar(100);not null"`            // Human readable name of the rule
+	Priority            int               `gorm:"not null;default:0;index"`              // Rules are applied in ascending priority order
+	Enabled             bool              `gorm:"not null;default:true"`                 // Disabled rules are never applied
+	CourierCode         string            `gorm:"type:varchar(50)"`                      // Match courier code, empty matches any courier
+	ServiceType         string            `gorm:"type:varchar(50)"`                      // Match service type, empty matches any service type
+	DestinationProvince string            `gorm:"type:varchar(100)"`                     // Match destination province, empty matches any province
+	MinWeight           int               `gorm:"not null;default:0"`                    // Minimum chargeable weight in grams, 0 for no minimum
+	MaxWeight           int               `gorm:"not null;default:0"`                    // Maximum chargeable weight in grams, 0 for no maximum
+	MinCartValue        int               `gorm:"not null;default:0"`                    // Minimum cart value, 0 for no minimum
+	MaxCartValue        int               `gorm:"not null;default:0"`                    // Maximum cart value, 0 for no maximum
+	Action              PricingRuleAction `gorm:"type:varchar(50);not null"`             // Action applied to the price when the rule matches
+	Value               float64           `gorm:"type:decimal(12,2);not null;default:0"` // Percentage or amount used by the action
+	CreatedAt           time.Time         `gorm:"autoCreateTime"`                        // Timestamp when the record was created
+	UpdatedAt           time.Time         `gorm:"autoUpdateTime"`                        // Timestamp
+	DeletedAt           gorm.DeletedAt    `gorm:"index"`                                 // Soft delete field
+}
+
+// TableName returns the name of the table in the database
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Province struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement:false"`   // Official province ID from the master dataset
+	Name      string    `gorm:"type:varchar(100);not null;index"` // Name of the province
+	CreatedAt time.Time `gorm:"autoCreateTime"`                   // Timestamp when the record was created
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`                   // Timestamp
+}
+
+// TableName returns the name of the table in the database
+func (Province) TableName() string {
+	return "provinces"
+}
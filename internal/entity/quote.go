@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Quote struct {
+	ID                    string    `gorm:"primaryKey;type:varchar(36)"`
+	MerchantID            string    `gorm:"type:varchar(36);index"`    // Merchant the quote was issued to, empty for the platform
+	CourierCode           string    `gorm:"type:varchar(50);not null"` // Code of the quoted courier
+	ServiceCode           string    `gorm:"type:varchar(50);not null"` // Code of the quoted courier service
+	ServiceType           string    `gorm:"type:varchar(50)"`          // Type of the quoted courier service
+	Currency              string    `gorm:"type:varchar(10)"`          // Currency of the quoted amounts
+	Price                 int       `gorm:"not null"`                  // Shipping price after pricing rules
+	OriginalPrice         int       `gorm:"not null"`                  // Shipping price returned by the provider
+	Total                 int       `gorm:"not null"`                  // All-in cost including insurance and COD fee
+	OriginAreaID          string    `gorm:"type:varchar(200)"`         // Provider area ID of the origin, empty when quoted by postal code
+	OriginPostalCode      string    `gorm:"type:varchar(20)"`          // Postal code of the origin
+	DestinationAreaID     string    `gorm:"type:varchar(200)"`         // Provider area ID of the destination, empty when quoted by postal code
+	DestinationPostalCode string    `gorm:"type:varchar(20)"`          // Postal code of the destination
+	Items                 string    `gorm:"type:text;not null"`        // JSON string of the quoted items
+	AppliedRules          string    `gorm:"type:varchar(255)"`         // JSON string of the applied pricing rule IDs
+	PriceDetail           string    `gorm:"type:text;not null"`        // JSON string of the quoted courier price
+	Signature             string    `gorm:"type:varchar(64);not null"` // HMAC of the quote content to detect tampering
+	ExpiresAt             time.Time `gorm:"not null;index"`            // The quote cannot be used after this time
+	CreatedAt             time.Time `gorm:"autoCreateTime"`            // Timestamp when the record was created
+	UpdatedAt             time.Time `gorm:"autoUpdateTime"`            // Timestamp
+}
+
+// TableName returns the name of the table in the database
+func (q *Quote) TableName() string {
+	return "quotes"
+}
+
+// BeforeCreate is a GORM hook that sets the ID when it has not been assigned yet
+func (q *Quote) BeforeCreate(tx *gorm.DB) (err error) {
+	if q.ID == "" {
+		id, _ := uuid.NewV7()
+		q.ID = id.String()
+	}
+	q.CreatedAt = time.Now()
+	q.UpdatedAt = time.Now()
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 type ShipmentTrackingLog struct {
 	ID              string `gorm:"primaryKey;type:varchar(36)"`
+	MerchantID      string `gorm:"type:varchar(36);index"` // Merchant that tracked the shipment, empty for the platform
 	CourierCode     string `gorm:"type:varchar(50);not null"`
 	Waybill         string `gorm:"type:varchar(255);not null"`
 	OriginInfo      string `gorm:"type:text;not null"`
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type Subdistrict struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement:false"`   // Official subdistrict (kelurahan/desa) ID from the master dataset
+	DistrictID uint      `gorm:"not null;index"`                   // ID of the district the subdistrict belongs to
+	Name       string    `gorm:"type:varchar(100);not null;index"` // Name of the subdistrict
+	PostalCode string    `gorm:"type:varchar(20);index"`           // Postal code of the subdistrict
+	CreatedAt  time.Time `gorm:"autoCreateTime"`                   // Timestamp when the record was created
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`                   // Timestamp
+
+	District District `gorm:"foreignKey:DistrictID"`
+}
+
+// TableName returns the name of the table in the database
+func (Subdistrict) TableName() string {
+	return "subdistricts"
+}
@@ -0,0 +1,27 @@
+package model
+
+type AddressParseRequest struct {
+	Address string `json:"address"` // Free-text address to parse
+}
+
+type ParsedAddress struct {
+	Normalized      string   `json:"normalized"`        // Address with its abbreviations expanded
+	Street          string   `json:"street"`            // Street, building and house number
+	RT              string   `json:"rt"`                // Rukun tetangga number
+	RW              string   `json:"rw"`                // Rukun warga number
+	Subdistrict     string   `json:"subdistrict"`       // Kelurahan or desa
+	District        string   `json:"district"`          // Kecamatan
+	City            string   `json:"city"`              // Name of the kota or kabupaten without its type
+	CityType        string   `json:"city_type"`         // Kota or Kabupaten, empty when unknown
+	Province        string   `json:"province"`          // Province of the address
+	PostalCode      string   `json:"postal_code"`       // Postal code of the address
+	PostalCodeValid *bool    `json:"postal_code_valid"` // Whether the postal code exists in the area master data, null when there is no postal code
+	SubdistrictID   uint     `json:"subdistrict_id"`    // Master data subdistrict matching the address, 0 when not found
+	Query           string   `json:"query"`             // Area search query derived from the address
+	Warnings        []string `json:"warnings"`          // Parts of the address that could not be read or checked
+}
+
+type ParsedAddressResp struct {
+	Response
+	Data ParsedAddress `json:"data"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type APIClientCreateRequest struct {
+	Name         string   `json:"name"`          // Name of the client
+	MerchantID   string   `json:"merchant_id"`   // Merchant the client acts for, empty for the platform
+	Scopes       []string `json:"scopes"`        // Scopes granted to the client: rates, tracking, shipments or admin
+	RateLimit    int      `json:"rate_limit"`    // Requests per window in every route group, overriding the group limits, 0 uses the configured limits
+	MonthlyQuota int      `json:"monthly_quota"` // Requests per calendar month, 0 uses the configured quota and -1 is unlimited
+}
+
+type APIClientUpdateRequest struct {
+	Scopes       []string `json:"scopes"`        // New scopes of the client, kept when omitted
+	Enabled      *bool    `json:"enabled"`       // Enables or disables the client, kept when omitted
+	RateLimit    *int     `json:"rate_limit"`    // New rate limit of the client, kept when omitted
+	MonthlyQuota *int     `json:"monthly_quota"` // New monthly quota of the client, kept when omitted
+}
+
+type APIClientResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	MerchantID   string     `json:"merchant_id"`
+	KeyPrefix    string     `json:"key_prefix"`        // Public part of the API key
+	APIKey       string     `json:"api_key,omitempty"` // Full API key, only returned when it is issued or rotated
+	Scopes       []string   `json:"scopes"`
+	Enabled      bool       `json:"enabled"`
+	RateLimit    int        `json:"rate_limit"`
+	MonthlyQuota int        `json:"monthly_quota"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	RotatedAt    *time.Time `json:"rotated_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type APIClientResp struct {
+	Response
+	Data APIClientResponse `json:"data"`
+}
+
+type APIClientListResp struct {
+	Response
+	Data []APIClientResponse `json:"data"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type FindAreaParams struct {
 	OriginalSubdistrictID string `json:"original_subdistrict_id"` // ID from the original subdistrict
 	OriginalPostalCode    string `json:"original_postal_code"`    // Postal code from the original subdistrict
@@ -16,3 +18,85 @@ type LocationResponse struct {
 	Address     string `json:"address"`      // Address of the area
 	PostalCode  string `json:"postal_code"`  // Postal code of the area
 }
+
+type Coordinate struct {
+	Latitude  float64 `json:"latitude"`  // Latitude in decimal degrees
+	Longitude float64 `json:"longitude"` // Longitude in decimal degrees
+}
+
+type AreaFilter struct {
+	Page          int     `form:"page"`           // Page number, starting at 1
+	Size          int     `form:"size"`           // Number of areas per page
+	LowConfidence bool    `form:"low_confidence"` // Only areas flagged for review or scoring below the minimum match score
+	SubdistrictID uint    `form:"subdistrict_id"` // Only areas of this subdistrict
+	PostalCode    string  `form:"postal_code"`    // Only areas with this postal code
+	ExternalID    string  `form:"external_id"`    // Only areas mapped to this provider area
+	MinScore      float64 `form:"-"`              // Minimum match score of a confident mapping
+}
+
+type AreaResponse struct {
+	ID                    uint      `json:"id"`
+	OriginalSubdistrictID uint      `json:"original_subdistrict_id"` // ID from the original subdistrict
+	OriginalPostalCode    string    `json:"original_postal_code"`    // Postal code from the original subdistrict
+	Description           string    `json:"description"`             // Name of the provider area
+	ExternalSource        string    `json:"external_source"`         // Name of the provider
+	ExternalID            string    `json:"external_id"`             // ID of the provider area
+	Latitude              float64   `json:"latitude"`                // Latitude of the area, 0 when unknown
+	Longitude             float64   `json:"longitude"`               // Longitude of the area, 0 when unknown
+	MatchScore            float64   `json:"match_score"`             // Confidence of the mapping (0-1), 0 when not scored
+	NeedsReview           bool      `json:"needs_review"`            // Mapping should be checked manually
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type AreaCreateRequest struct {
+	OriginalSubdistrictID uint    `json:"original_subdistrict_id"` // ID from the original subdistrict
+	OriginalPostalCode    string  `json:"original_postal_code"`    // Postal code from the original subdistrict
+	ExternalID            string  `json:"external_id"`             // ID of the provider area to map to
+	Description           string  `json:"description"`             // Name of the provider area
+	Latitude              float64 `json:"latitude"`                // Latitude of the area, 0 when unknown
+	Longitude             float64 `json:"longitude"`               // Longitude of the area, 0 when unknown
+	Reason                string  `json:"reason"`                  // Why the mapping is added
+	Actor                 string  `json:"-"`                       // Who makes the change
+}
+
+type AreaOverrideRequest struct {
+	ExternalID  string   `json:"external_id"` // ID of the provider area to map to
+	Description string   `json:"description"` // Name of the provider area, kept when empty
+	Latitude    *float64 `json:"latitude"`    // Latitude of the area, kept when omitted
+	Longitude   *float64 `json:"longitude"`   // Longitude of the area, kept when omitted
+	Reason      string   `json:"reason"`      // Why the mapping is corrected
+	Actor       string   `json:"-"`           // Who makes the change
+}
+
+type AreaChangeRequest struct {
+	Reason string `json:"reason"` // Why the area is re-resolved or deleted
+	Actor  string `json:"-"`      // Who makes the change
+}
+
+type AreaAuditResponse struct {
+	ID        uint          `json:"id"`
+	AreaID    uint          `json:"area_id"`
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	Reason    string        `json:"reason"`
+	Before    *AreaResponse `json:"before"` // Area before the change, null on create
+	After     *AreaResponse `json:"after"`  // Area after the change, null on delete
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type AreaResp struct {
+	Response
+	Data AreaResponse `json:"data"`
+}
+
+type AreaListResp struct {
+	Response
+	Data []AreaResponse     `json:"data"`
+	Meta PaginationResponse `json:"meta"`
+}
+
+type AreaAuditListResp struct {
+	Response
+	Data []AreaAuditResponse `json:"data"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type BulkRateJobResponse struct {
+	ID            string     `json:"id"`             // ID of the job
+	FileName      string     `json:"file_name"`      // Name of the uploaded file
+	Status        string     `json:"status"`         // pending, processing, completed or failed
+	TotalRows     int        `json:"total_rows"`     // Number of routes in the upload
+	ProcessedRows int        `json:"processed_rows"` // Number of routes priced so far
+	FailedRows    int        `json:"failed_rows"`    // Number of routes that could not be priced
+	Progress      float64    `json:"progress"`       // Percentage of processed routes
+	Error         string     `json:"error,omitempty"`
+	StartedAt     *time.Time `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type BulkRateJobResp struct {
+	Response
+	Data BulkRateJobResponse `json:"data"`
+}
+
+// BulkRateRow is a single route parsed from an uploaded CSV
+type BulkRateRow struct {
+	Line                  int    // Line number in the uploaded file
+	OriginPostalCode      string // Postal code of the origin
+	DestinationPostalCode string // Postal code of the destination
+	Metrics
+	CourierCode string // Comma separated courier codes
+}
@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+)
+
+func APIClientToResponse(c *entity.APIClient) model.APIClientResponse {
+	return model.APIClientResponse{
+		ID:           c.ID,
+		Name:         c.Name,
+		MerchantID:   c.MerchantID,
+		KeyPrefix:    c.KeyPrefix,
+		Scopes:       c.ScopeList(),
+		Enabled:      c.Enabled,
+		RateLimit:    c.RateLimit,
+		MonthlyQuota: c.MonthlyQuota,
+		LastUsedAt:   c.LastUsedAt,
+		RotatedAt:    c.RotatedAt,
+		CreatedAt:    c.CreatedAt,
+	}
+}
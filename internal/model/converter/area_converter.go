@@ -2,7 +2,9 @@ package converter
 
 import (
 	"encoding/json"
+	"fmt"
 	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
 )
 
 func AreaFromJSONString(jsonString string) (*entity.Area, error) {
@@ -17,3 +19,51 @@ func AreaFromJSONString(jsonString string) (*entity.Area, error) {
 
 	return &area, nil
 }
+
+func AreaToResponse(area *entity.Area) model.AreaResponse {
+	return model.AreaResponse{
+		ID:                    area.ID,
+		OriginalSubdistrictID: area.OriginalSubdistrictID,
+		OriginalPostalCode:    area.OriginalPostalCode,
+		Description:           area.Description,
+		ExternalSource:        area.ExternalSource,
+		ExternalID:            area.ExternalID,
+		Latitude:              area.Latitude,
+		Longitude:             area.Longitude,
+		MatchScore:            area.MatchScore,
+		NeedsReview:           area.NeedsReview,
+		CreatedAt:             area.CreatedAt,
+		UpdatedAt:             area.UpdatedAt,
+	}
+}
+
+func AreaAuditToResponse(audit *entity.AreaAudit) (*model.AreaAuditResponse, error) {
+	resp := &model.AreaAuditResponse{
+		ID:        audit.ID,
+		AreaID:    audit.AreaID,
+		Action:    audit.Action,
+		Actor:     audit.Actor,
+		Reason:    audit.Reason,
+		CreatedAt: audit.CreatedAt,
+	}
+
+	before, err := AreaFromJSONString(audit.Before)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling area before change: %w", err)
+	}
+	if before != nil {
+		r := AreaToResponse(before)
+		resp.Before = &r
+	}
+
+	after, err := AreaFromJSONString(audit.After)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling area after change: %w", err)
+	}
+	if after != nil {
+		r := AreaToResponse(after)
+		resp.After = &r
+	}
+
+	return resp, nil
+}
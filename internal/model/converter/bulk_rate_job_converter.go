@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"math"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+)
+
+func BulkRateJobToResponse(j *entity.BulkRateJob) *model.BulkRateJobResponse {
+	progress := 0.0
+	if j.TotalRows > 0 {
+		progress = math.Round(float64(j.ProcessedRows)/float64(j.TotalRows)*10000) / 100
+	}
+
+	return &model.BulkRateJobResponse{
+		ID:            j.ID,
+		FileName:      j.FileName,
+		Status:        string(j.Status),
+		TotalRows:     j.TotalRows,
+		ProcessedRows: j.ProcessedRows,
+		FailedRows:    j.FailedRows,
+		Progress:      progress,
+		Error:         j.Error,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+		CreatedAt:     j.CreatedAt,
+	}
+}
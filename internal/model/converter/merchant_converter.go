@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"strings"
+)
+
+func MerchantToResponse(m *entity.Merchant, credentials model.ProviderCredentials) model.MerchantResponse {
+	return model.MerchantResponse{
+		ID:                      m.ID,
+		Name:                    m.Name,
+		Enabled:                 m.Enabled,
+		BiteshipAPIKey:          maskSecret(credentials.BiteshipAPIKey),
+		DefaultCouriers:         m.DefaultCouriers,
+		DefaultOriginPostalCode: m.DefaultOriginPostalCode,
+		CreatedAt:               m.CreatedAt,
+		UpdatedAt:               m.UpdatedAt,
+	}
+}
+
+// maskSecret hides all but the last four characters of a secret
+func maskSecret(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", 8) + value[len(value)-4:]
+}
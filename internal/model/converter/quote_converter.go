@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+)
+
+func QuoteToResponse(q *entity.Quote) (*model.QuoteResponse, error) {
+	var resp model.QuoteResponse
+	resp.ID = q.ID
+	resp.Origin = model.QuoteLocation{AreaID: q.OriginAreaID, PostalCode: q.OriginPostalCode}
+	resp.Destination = model.QuoteLocation{AreaID: q.DestinationAreaID, PostalCode: q.DestinationPostalCode}
+	resp.ExpiresAt = q.ExpiresAt
+	resp.CreatedAt = q.CreatedAt
+
+	if err := json.Unmarshal([]byte(q.Items), &resp.Items); err != nil {
+		return nil, fmt.Errorf("error unmarshalling quote items: %w", err)
+	}
+	if err := json.Unmarshal([]byte(q.PriceDetail), &resp.Price); err != nil {
+		return nil, fmt.Errorf("error unmarshalling quote price: %w", err)
+	}
+	if q.AppliedRules != "" {
+		if err := json.Unmarshal([]byte(q.AppliedRules), &resp.AppliedRules); err != nil {
+			return nil, fmt.Errorf("error unmarshalling quote applied rules: %w", err)
+		}
+	}
+
+	return &resp, nil
+}
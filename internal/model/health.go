@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ProviderHealth reports the circuit breaker of a provider. Calls fail fast without reaching the provider while
+// the circuit is open.
+type ProviderHealth struct {
+	Circuit             string     `json:"circuit"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Status    string                    `json:"status"`
+	Message   string                    `json:"message"`
+	Providers map[string]ProviderHealth `json:"providers"`
+}
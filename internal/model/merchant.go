@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// ProviderCredentials holds the provider accounts of a merchant, it is only ever stored encrypted
+type ProviderCredentials struct {
+	BiteshipAPIKey string `json:"biteship_api_key"`
+}
+
+type MerchantCreateRequest struct {
+	Name                    string              `json:"name"`
+	Credentials             ProviderCredentials `json:"credentials"`                // Provider accounts of the merchant
+	DefaultCouriers         string              `json:"default_couriers"`           // Comma separated couriers quoted when a request does not name any
+	DefaultOriginPostalCode string              `json:"default_origin_postal_code"` // Origin used when a request does not give one
+}
+
+type MerchantUpdateRequest struct {
+	Name                    *string              `json:"name"`
+	Enabled                 *bool                `json:"enabled"`
+	Credentials             *ProviderCredentials `json:"credentials"` // Replaces the provider accounts, kept when omitted
+	DefaultCouriers         *string              `json:"default_couriers"`
+	DefaultOriginPostalCode *string              `json:"default_origin_postal_code"`
+}
+
+type MerchantResponse struct {
+	ID                      string    `json:"id"`
+	Name                    string    `json:"name"`
+	Enabled                 bool      `json:"enabled"`
+	BiteshipAPIKey          string    `json:"biteship_api_key"` // Masked API key, only the last characters are shown
+	DefaultCouriers         string    `json:"default_couriers"`
+	DefaultOriginPostalCode string    `json:"default_origin_postal_code"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+type MerchantResp struct {
+	Response
+	Data MerchantResponse `json:"data"`
+}
+
+type MerchantListResp struct {
+	Response
+	Data []MerchantResponse `json:"data"`
+}
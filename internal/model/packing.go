@@ -0,0 +1,62 @@
+package model
+
+type PackingStrategy string
+
+const (
+	PackingStrategyFewest   PackingStrategy = "fewest"   // Use as few boxes as possible
+	PackingStrategyCheapest PackingStrategy = "cheapest" // Use the cheapest combination of boxes
+)
+
+const (
+	MaxPackingQuantity = 500  // Most units of a single item that can be packed
+	MaxPackingUnits    = 1000 // Most units of all items that can be packed in one request
+)
+
+type PackingOptions struct {
+	Strategy PackingStrategy `json:"strategy"` // fewest or cheapest, defaults to fewest
+}
+
+type PackingRequest struct {
+	PackingOptions
+	Items []ItemRequest `json:"items"` // Items to pack
+}
+
+type PackedItem struct {
+	Name     string `json:"name"`     // Name of the item
+	Quantity int    `json:"quantity"` // Number of units of the item in the box
+}
+
+type PackedBox struct {
+	BoxID    uint         `json:"box_id"`   // ID of the box type, 0 for items shipped in their own packaging
+	BoxName  string       `json:"box_name"` // Name of the box type
+	Metrics               // Outer dimensions and total weight of the package
+	Value    int          `json:"value"`     // Total value of the packed items
+	Cost     int          `json:"cost"`      // Cost of the box
+	FillRate float64      `json:"fill_rate"` // Share of the box volume used by the items
+	Items    []PackedItem `json:"items"`     // Items in the box
+}
+
+type PackingResult struct {
+	Strategy  PackingStrategy `json:"strategy"`   // Strategy used to choose the boxes
+	Packages  []PackedBox     `json:"packages"`   // Packages to ship
+	TotalCost int             `json:"total_cost"` // Total cost of the boxes
+}
+
+type PackingResp struct {
+	Response
+	Data PackingResult `json:"data"`
+}
+
+// ToItemRequests converts the packages to one shippable item each
+func (r PackingResult) ToItemRequests() []ItemRequest {
+	items := make([]ItemRequest, 0, len(r.Packages))
+	for _, pkg := range r.Packages {
+		items = append(items, ItemRequest{
+			Name:     pkg.BoxName,
+			Price:    pkg.Value,
+			Metrics:  pkg.Metrics,
+			Quantity: 1,
+		})
+	}
+	return items
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type QuoteResponse struct {
+	ID           string        `json:"id"`            // Quote ID to reference at checkout
+	Origin       QuoteLocation `json:"origin"`        // Resolved origin of the quote
+	Destination  QuoteLocation `json:"destination"`   // Resolved destination of the quote
+	Items        []ItemRequest `json:"items"`         // Items the quote was priced for
+	Price        CourierPrice  `json:"price"`         // Quoted courier price
+	AppliedRules []uint        `json:"applied_rules"` // IDs of the pricing rules applied to the quote
+	ExpiresAt    time.Time     `json:"expires_at"`    // The quote cannot be used after this time
+	CreatedAt    time.Time     `json:"created_at"`    // When the quote was issued
+}
+
+type QuoteLocation struct {
+	AreaID     string `json:"area_id"`     // Provider area ID, empty when quoted by postal code
+	PostalCode string `json:"postal_code"` // Postal code of the location
+}
+
+type QuoteResp struct {
+	Response
+	Data QuoteResponse `json:"data"`
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"math"
+	"time"
+)
+
 type Metrics struct {
 	Length int `json:"length"` // Length of the item in centimeters
 	Width  int `json:"width"`  // Width of the item in centimeters
@@ -15,33 +20,200 @@ type ItemRequest struct {
 }
 
 type CourierRateRequest struct {
-	OriginSubdistrictID      string        `json:"origin_subdistrict_id"`
-	DestinationSubdistrictID string        `json:"destination_subdistrict_id"`
-	OriginQuery              string        `json:"origin_query"`            // Optional query for origin, e.g., postal code or subdistrict name
-	DestinationQuery         string        `json:"destination_query"`       // Optional query for destination, e.g., postal code or subdistrict name
-	OriginPostalCode         string        `json:"origin_postal_code"`      // Postal code of the origin area
-	DestinationPostalCode    string        `json:"destination_postal_code"` // Postal code of the destination area
-	CourierCode              string        `json:"courier_code"`            // Code of the courier service
-	Items                    []ItemRequest `json:"items"`                   // List of items to be shipped
+	OriginSubdistrictID      string          `json:"origin_subdistrict_id"`
+	DestinationSubdistrictID string          `json:"destination_subdistrict_id"`
+	OriginQuery              string          `json:"origin_query"`            // Optional query for origin, e.g., postal code or subdistrict name
+	DestinationQuery         string          `json:"destination_query"`       // Optional query for destination, e.g., postal code or subdistrict name
+	OriginAddress            string          `json:"origin_address"`          // Optional free-text origin address, used to derive the origin query
+	DestinationAddress       string          `json:"destination_address"`     // Optional free-text destination address, used to derive the destination query
+	OriginPostalCode         string          `json:"origin_postal_code"`      // Postal code of the origin area
+	DestinationPostalCode    string          `json:"destination_postal_code"` // Postal code of the destination area
+	OriginLatitude           *float64        `json:"origin_latitude"`         // Optional origin latitude, required by instant couriers
+	OriginLongitude          *float64        `json:"origin_longitude"`        // Optional origin longitude, required by instant couriers
+	DestinationLatitude      *float64        `json:"destination_latitude"`    // Optional destination latitude, required by instant couriers
+	DestinationLongitude     *float64        `json:"destination_longitude"`   // Optional destination longitude, required by instant couriers
+	IncludeInstant           bool            `json:"include_instant"`         // Also quote the configured instant couriers when coordinates are known
+	CourierCode              string          `json:"courier_code"`            // Code of the courier service
+	Items                    []ItemRequest   `json:"items"`                   // List of items to be shipped
+	Insurance                bool            `json:"insurance"`               // Quote shipment insurance
+	COD                      bool            `json:"cod"`                     // Quote cash on delivery
+	DeclaredValue            int             `json:"declared_value"`          // Declared value used for insurance and COD, defaults to the cart value
+	Packing                  *PackingOptions `json:"packing"`                 // Pack the items into boxes before quoting
+	SortBy                   RateSortBy      `json:"sort_by"`                 // Optional sort order of the prices: price or etd
+	Filter                   RateFilter      `json:"filter"`                  // Optional filters applied to the prices
+	BypassCache              bool            `json:"-"`                       // Skip the rate cache lookup, set from request header
+	SkipQuote                bool            `json:"-"`                       // Do not persist quotes, for internal bulk pricing
+}
+
+type RateSortBy string
+
+const (
+	RateSortByPrice RateSortBy = "price" // Cheapest first
+	RateSortByETD   RateSortBy = "etd"   // Fastest first
+)
+
+type RateFilter struct {
+	ServiceTypes          []string `json:"service_types"`           // Only return these service types
+	RequireCOD            bool     `json:"require_cod"`             // Only return services supporting cash on delivery
+	RequireInsurance      bool     `json:"require_insurance"`       // Only return services supporting insurance
+	RequireInstantWaybill bool     `json:"require_instant_waybill"` // Only return services issuing a waybill instantly
+	MaxPrice              int      `json:"max_price"`               // Only return prices whose all-in total is up to this amount, 0 for no limit
+}
+
+type CourierCapabilities struct {
+	CashOnDelivery    bool     `json:"cash_on_delivery"`   // Service supports cash on delivery
+	Insurance         bool     `json:"insurance"`          // Service supports shipment insurance
+	InstantWaybill    bool     `json:"instant_waybill"`    // Service issues the waybill ID instantly
+	ProofOfDelivery   bool     `json:"proof_of_delivery"`  // Service provides proof of delivery
+	CollectionMethods []string `json:"collection_methods"` // How the courier collects the parcel, e.g. pickup or drop_off
+}
+
+type PriceFeeType string
+
+const (
+	PriceFeeTypeTax       PriceFeeType = "tax"       // Tax charged by the provider
+	PriceFeeTypeInsurance PriceFeeType = "insurance" // Insurance premium
+	PriceFeeTypeCOD       PriceFeeType = "cod"       // Cash on delivery fee
+)
+
+type PriceBreakdown struct {
+	Shipping  int `json:"shipping"`  // Shipping price after pricing rules
+	Insurance int `json:"insurance"` // Insurance premium, 0 when not requested
+	CODFee    int `json:"cod_fee"`   // Cash on delivery fee, 0 when not requested
+	Tax       int `json:"tax"`       // Taxes charged by the provider
+	Total     int `json:"total"`     // All-in cost of the shipment, the sum of the lines above
+}
+
+type PriceFee struct {
+	Type   PriceFeeType `json:"type"`   // Kind of fee
+	Name   string       `json:"name"`   // Name of the fee as given by the provider
+	Amount int          `json:"amount"` // Amount of the fee in cents
 }
 
 type CourierPrice struct {
-	CourierCode string `json:"courier_code"` // Code of the courier service
-	CourierName string `json:"courier_name"` // Name of the courier service
-	ServiceType string `json:"service_type"` // Type of service provided by the courier
-	ServiceName string `json:"service_name"` // Name of the service provided by the courier
-	ServiceCode string `json:"service_code"` // Code of the service provided by the courier
-	Price       int    `json:"price"`        // Price of the service in cents
-	ETD         string `json:"etd"`          // Estimated time of delivery
+	CourierCode   string              `json:"courier_code"`     // Code of the courier service
+	CourierName   string              `json:"courier_name"`     // Name of the courier service
+	ServiceType   string              `json:"service_type"`     // Type of service provided by the courier
+	ServiceName   string              `json:"service_name"`     // Name of the service provided by the courier
+	ServiceCode   string              `json:"service_code"`     // Code of the service provided by the courier
+	Price         int                 `json:"price"`            // Final price of the service in cents after pricing rules
+	OriginalPrice int                 `json:"original_price"`   // Price returned by the provider before pricing rules
+	AppliedRules  []uint              `json:"applied_rules"`    // IDs of the pricing rules applied to the price
+	Currency      string              `json:"currency"`         // Currency of the price, e.g. IDR
+	Fees          []PriceFee          `json:"fees"`             // Itemized fees included in the quote
+	Breakdown     PriceBreakdown      `json:"breakdown"`        // Shipping, insurance, COD fee and tax making up the total
+	ETD           string              `json:"etd"`              // Estimated time of delivery
+	Weight        WeightBreakdown     `json:"weight"`           // Actual, volumetric and chargeable weight for this courier
+	Capabilities  CourierCapabilities `json:"capabilities"`     // Optional features supported by the service
+	Score         float64             `json:"score"`            // Weighted score of price, speed and on-time rate
+	Recommended   bool                `json:"recommended"`      // Highest scoring option among the prices
+	QuoteID       string              `json:"quote_id"`         // ID of the persisted quote for this price
+	QuoteExpires  *time.Time          `json:"quote_expires_at"` // The quote cannot be used after this time
 }
 
 type CourierRate struct {
 	Origin      LocationResponse `json:"origin"`
 	Destination LocationResponse `json:"destination"`
-	Prices      []CourierPrice   `json:"prices"` // List of prices for the courier services
+	Prices      []CourierPrice   `json:"prices"`                // List of prices for the courier services
+	Packing     *PackingResult   `json:"packing,omitempty"`     // Packages the items were quoted as, when packing was requested
+	DistanceKm  *float64         `json:"distance_km,omitempty"` // Straight-line distance between origin and destination coordinates
 }
 
 type CourierRateResponse struct {
 	Response
 	Data CourierRate `json:"data"` // Data containing the origin, destination, and prices for the courier services
 }
+
+type RateCacheStatus string
+
+const (
+	RateCacheHit    RateCacheStatus = "hit"    // Response served from cache
+	RateCacheMiss   RateCacheStatus = "miss"   // Response fetched from provider and cached
+	RateCacheBypass RateCacheStatus = "bypass" // Cache lookup skipped on client request
+)
+
+type RateMeta struct {
+	Cache RateCacheStatus `json:"cache"` // Indicates whether the rates were served from cache
+}
+
+// VolumetricWeight returns the volumetric weight in grams for the given divisor (cm³ per kg)
+func (m Metrics) VolumetricWeight(divisor int) int {
+	if divisor <= 0 {
+		return 0
+	}
+	volume := m.Length * m.Width * m.Height
+	return int(math.Ceil(float64(volume) * 1000 / float64(divisor)))
+}
+
+// ChargeableWeight returns the greater of the actual and volumetric weight in grams
+func (m Metrics) ChargeableWeight(divisor int) int {
+	return max(m.Weight, m.VolumetricWeight(divisor))
+}
+
+type WeightBreakdown struct {
+	Actual     int `json:"actual"`     // Total actual weight in grams
+	Volumetric int `json:"volumetric"` // Total volumetric weight in grams
+	Chargeable int `json:"chargeable"` // Total weight billed by the courier in grams
+	Divisor    int `json:"divisor"`    // Volumetric divisor used by the courier
+}
+
+// CartValue returns the total value of the items
+func (r CourierRateRequest) CartValue() int {
+	total := 0
+	for _, item := range r.Items {
+		total += item.Price * item.Quantity
+	}
+	return total
+}
+
+// InsuredValue returns the declared value, or the cart value when none was declared
+func (r CourierRateRequest) InsuredValue() int {
+	if r.DeclaredValue > 0 {
+		return r.DeclaredValue
+	}
+	return r.CartValue()
+}
+
+// NewWeightBreakdown sums actual, volumetric and chargeable weight of the items for the given divisor.
+// Chargeable weight is summed per item since items are shipped as separate packages.
+func NewWeightBreakdown(items []ItemRequest, divisor int) WeightBreakdown {
+	breakdown := WeightBreakdown{Divisor: divisor}
+	for _, item := range items {
+		breakdown.Actual += item.Weight * item.Quantity
+		breakdown.Volumetric += item.VolumetricWeight(divisor) * item.Quantity
+		breakdown.Chargeable += item.ChargeableWeight(divisor) * item.Quantity
+	}
+	return breakdown
+}
+
+type BatchRateRequest struct {
+	Legs []CourierRateRequest `json:"legs"` // Origin to destination legs, each with its own items and couriers
+}
+
+type BatchRateLeg struct {
+	Index   int          `json:"index"`          // Position of the leg in the request
+	Status  string       `json:"status"`         // success or failed
+	Code    int          `json:"code"`           // HTTP status code of the leg
+	Message string       `json:"message"`        // Error message when the leg failed
+	Data    *CourierRate `json:"data,omitempty"` // Rates of the leg when it succeeded
+}
+
+type CombinedCourierPrice struct {
+	CourierCode string   `json:"courier_code"` // Code of the courier used for every leg
+	CourierName string   `json:"courier_name"` // Name of the courier used for every leg
+	ServiceCode string   `json:"service_code"` // Code of the service used for every leg
+	ServiceName string   `json:"service_name"` // Name of the service used for every leg
+	Price       int      `json:"price"`        // Sum of the shipping prices of all legs
+	Total       int      `json:"total"`        // Sum of the all-in costs of all legs
+	QuoteIDs    []string `json:"quote_ids"`    // Quote ID of each leg in leg order
+}
+
+type BatchRate struct {
+	Legs     []BatchRateLeg         `json:"legs"`     // Result of each leg in request order
+	Combined []CombinedCourierPrice `json:"combined"` // Services available on every leg, cheapest first
+}
+
+type BatchRateResponse struct {
+	Response
+	Data BatchRate `json:"data"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "testing"
+
+func TestMetricsWeight(t *testing.T) {
+	tests := []struct {
+		name           string
+		metrics        Metrics
+		divisor        int
+		wantVolumetric int
+		wantChargeable int
+	}{
+		{"heavy and small", Metrics{Length: 10, Width: 10, Height: 10, Weight: 2000}, 6000, 167, 2000},
+		{"light and bulky", Metrics{Length: 60, Width: 40, Height: 30, Weight: 1500}, 6000, 12000, 12000},
+		{"rounded up to the gram", Metrics{Length: 7, Width: 7, Height: 7, Weight: 10}, 5000, 69, 69},
+		{"other divisor", Metrics{Length: 60, Width: 40, Height: 30, Weight: 1500}, 4000, 18000, 18000},
+		{"no divisor", Metrics{Length: 60, Width: 40, Height: 30, Weight: 1500}, 0, 0, 1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metrics.VolumetricWeight(tt.divisor); got != tt.wantVolumetric {
+				t.Errorf("VolumetricWeight(%d) = %d, want %d", tt.divisor, got, tt.wantVolumetric)
+			}
+			if got := tt.metrics.ChargeableWeight(tt.divisor); got != tt.wantChargeable {
+				t.Errorf("ChargeableWeight(%d) = %d, want %d", tt.divisor, got, tt.wantChargeable)
+			}
+		})
+	}
+}
+
+func TestNewWeightBreakdown(t *testing.T) {
+	items := []ItemRequest{
+		{Metrics: Metrics{Length: 10, Width: 10, Height: 10, Weight: 2000}, Quantity: 2},
+		{Metrics: Metrics{Length: 60, Width: 40, Height: 30, Weight: 1500}, Quantity: 1},
+	}
+
+	// chargeable weight is taken per item, so it is more than the greater of the summed weights
+	want := WeightBreakdown{Actual: 5500, Volumetric: 12334, Chargeable: 16000, Divisor: 6000}
+	if got := NewWeightBreakdown(items, 6000); got != want {
+		t.Errorf("NewWeightBreakdown() = %+v, want %+v", got, want)
+	}
+}
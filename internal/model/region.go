@@ -0,0 +1,47 @@
+package model
+
+type ProvinceResponse struct {
+	ID   uint   `json:"id"`   // Official province ID
+	Name string `json:"name"` // Name of the province
+}
+
+type CityResponse struct {
+	ID         uint   `json:"id"`          // Official city ID
+	ProvinceID uint   `json:"province_id"` // ID of the province the city belongs to
+	Type       string `json:"type"`        // Kota or Kabupaten
+	Name       string `json:"name"`        // Name of the city without its type
+}
+
+type DistrictResponse struct {
+	ID     uint   `json:"id"`      // Official district ID
+	CityID uint   `json:"city_id"` // ID of the city the district belongs to
+	Name   string `json:"name"`    // Name of the district
+}
+
+type SubdistrictResponse struct {
+	ID         uint   `json:"id"`          // Official subdistrict ID, usable as origin_subdistrict_id or destination_subdistrict_id
+	DistrictID uint   `json:"district_id"` // ID of the district the subdistrict belongs to
+	Name       string `json:"name"`        // Name of the subdistrict
+	PostalCode string `json:"postal_code"` // Postal code of the subdistrict
+	AreaID     string `json:"area_id"`     // Provider area ID the subdistrict is mapped to, empty when not mapped yet
+}
+
+type ProvinceListResp struct {
+	Response
+	Data []ProvinceResponse `json:"data"`
+}
+
+type CityListResp struct {
+	Response
+	Data []CityResponse `json:"data"`
+}
+
+type DistrictListResp struct {
+	Response
+	Data []DistrictResponse `json:"data"`
+}
+
+type SubdistrictListResp struct {
+	Response
+	Data []SubdistrictResponse `json:"data"`
+}
@@ -56,3 +56,52 @@ func NotFound(message string) *ServiceResponse {
 		Message:    message,
 	}
 }
+
+func Gone(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusGone,
+		Message:    message,
+	}
+}
+
+func UnprocessableEntity(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    message,
+	}
+}
+
+func Conflict(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusConflict,
+		Message:    message,
+	}
+}
+
+func Unauthorized(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusUnauthorized,
+		Message:    message,
+	}
+}
+
+func Forbidden(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusForbidden,
+		Message:    message,
+	}
+}
+
+func TooManyRequests(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    message,
+	}
+}
+
+func ServiceUnavailable(message string) *ServiceResponse {
+	return &ServiceResponse{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    message,
+	}
+}
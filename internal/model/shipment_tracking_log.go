@@ -19,6 +19,16 @@ type ShipmentTrackingResp struct {
 	Data ShipmentTrackingResponse `json:"data"`
 }
 
+type CourierDetection struct {
+	CourierCode string             `json:"courier_code"` // Courier the tracking was found with
+	Candidates  []CourierCandidate `json:"candidates"`   // Couriers whose waybill format matches, most likely first
+}
+
+type CourierCandidate struct {
+	CourierCode string  `json:"courier_code"`
+	Likelihood  float64 `json:"likelihood"` // How likely the waybill belongs to the courier, from 0 to 1
+}
+
 type ShipmentTrackingResponse struct {
 	CourierCode     string                `json:"courier_code"`
 	Waybill         string                `json:"waybill"`
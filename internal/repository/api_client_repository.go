@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+	"time"
+)
+
+type APIClientRepository struct {
+	Repository[entity.APIClient]
+}
+
+func NewAPIClientRepository() *APIClientRepository {
+	return &APIClientRepository{}
+}
+
+// FindByKeyPrefix returns the client owning the API key with the given public prefix
+func (r *APIClientRepository) FindByKeyPrefix(db *gorm.DB, prefix string) (*entity.APIClient, error) {
+	var client entity.APIClient
+	if err := db.Where("key_prefix = ?", prefix).First(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FindAll returns every client, newest first
+func (r *APIClientRepository) FindAll(db *gorm.DB) ([]entity.APIClient, error) {
+	var clients []entity.APIClient
+	err := db.Order("created_at DESC").Find(&clients).Error
+	return clients, err
+}
+
+// TouchLastUsed records that a client was used, skipping the write when it was already recorded recently
+func (r *APIClientRepository) TouchLastUsed(db *gorm.DB, id string, now time.Time, every time.Duration) error {
+	return db.Model(&entity.APIClient{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-every)).
+		UpdateColumn("last_used_at", now).Error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type AreaAuditRepository struct {
+	Repository[entity.AreaAudit]
+}
+
+func NewAreaAuditRepository() *AreaAuditRepository {
+	return &AreaAuditRepository{}
+}
+
+// FindByAreaID returns the audit trail of an area, newest first
+func (r *AreaAuditRepository) FindByAreaID(db *gorm.DB, areaID uint) ([]entity.AreaAudit, error) {
+	var audits []entity.AreaAudit
+	err := db.Where("area_id = ?", areaID).Order("id DESC").Find(&audits).Error
+	return audits, err
+}
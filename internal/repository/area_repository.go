@@ -18,8 +18,15 @@ func NewAreaRepository() *AreaRepository {
 func (r *AreaRepository) SaveOrCreate(db *gorm.DB, area *entity.Area) error {
 	var existingArea entity.Area
 
-	// Check if the area already exists
-	err := db.Model(&existingArea).Where("external_id = ?", area.ExternalID).First(&existingArea).Error
+	// Check if the area already exists. Subdistricts are mapped one to one, while several subdistricts
+	// may share the same external area.
+	query := db.Model(&existingArea).Where("external_source = ?", area.ExternalSource)
+	if area.OriginalSubdistrictID > 0 {
+		query = query.Where("original_subdistrict_id = ?", area.OriginalSubdistrictID)
+	} else {
+		query = query.Where("original_subdistrict_id = 0 AND external_id = ?", area.ExternalID)
+	}
+	err := query.First(&existingArea).Error
 	if err == nil {
 		// Area exists, update it
 		area.ID = existingArea.ID // Preserve the ID for update
@@ -56,3 +63,48 @@ func (r *AreaRepository) FindByParameter(db *gorm.DB, params model.FindAreaParam
 	err := query.First(&area).Error
 	return &area, err
 }
+
+// FindBySubdistrictIDs returns the areas of an external source mapped to the given subdistricts
+func (r *AreaRepository) FindBySubdistrictIDs(db *gorm.DB, externalSource string, subdistrictIDs []uint) ([]entity.Area, error) {
+	var areas []entity.Area
+	err := db.Where("external_source = ? AND original_subdistrict_id IN ?", externalSource, subdistrictIDs).Find(&areas).Error
+	return areas, err
+}
+
+// FindPage returns a page of areas matching the filter together with the total number of matching areas
+func (r *AreaRepository) FindPage(db *gorm.DB, filter model.AreaFilter) ([]entity.Area, int64, error) {
+	query := db.Model(&entity.Area{})
+	if filter.LowConfidence {
+		query = query.Where("needs_review = ? OR match_score < ?", true, filter.MinScore)
+	}
+	if filter.SubdistrictID > 0 {
+		query = query.Where("original_subdistrict_id = ?", filter.SubdistrictID)
+	}
+	if filter.PostalCode != "" {
+		query = query.Where("original_postal_code = ?", filter.PostalCode)
+	}
+	if filter.ExternalID != "" {
+		query = query.Where("external_id = ?", filter.ExternalID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "id ASC"
+	if filter.LowConfidence {
+		order = "match_score ASC, id ASC"
+	}
+
+	var areas []entity.Area
+	err := query.Order(order).Offset((filter.Page - 1) * filter.Size).Limit(filter.Size).Find(&areas).Error
+	return areas, total, err
+}
+
+// FindBySource returns every area of an external source
+func (r *AreaRepository) FindBySource(db *gorm.DB, externalSource string) ([]entity.Area, error) {
+	var areas []entity.Area
+	err := db.Where("external_source = ?", externalSource).Find(&areas).Error
+	return areas, err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type BoxRepository struct {
+	Repository[entity.Box]
+}
+
+func NewBoxRepository() *BoxRepository {
+	return &BoxRepository{}
+}
+
+// FindByMerchant returns the enabled boxes of a merchant, falling back to the default set
+// when the merchant has not configured any
+func (r *BoxRepository) FindByMerchant(db *gorm.DB, merchantID string) ([]entity.Box, error) {
+	var boxes []entity.Box
+	if merchantID != "" {
+		if err := db.Where("merchant_id = ? AND enabled = ?", merchantID, true).Find(&boxes).Error; err != nil {
+			return nil, err
+		}
+		if len(boxes) > 0 {
+			return boxes, nil
+		}
+	}
+
+	err := db.Where("merchant_id = ? AND enabled = ?", "", true).Find(&boxes).Error
+	return boxes, err
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type BulkRateJobRepository struct {
+	Repository[entity.BulkRateJob]
+}
+
+func NewBulkRateJobRepository() *BulkRateJobRepository {
+	return &BulkRateJobRepository{}
+}
+
+// FindUnfinished returns jobs that were queued or interrupted while processing, oldest first
+func (r *BulkRateJobRepository) FindUnfinished(db *gorm.DB) ([]entity.BulkRateJob, error) {
+	var jobs []entity.BulkRateJob
+	err := db.Where("status IN ?", []entity.BulkRateJobStatus{entity.BulkRateJobPending, entity.BulkRateJobProcessing}).
+		Order("created_at ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// UpdateProgress stores the row counters of a job without touching its other fields
+func (r *BulkRateJobRepository) UpdateProgress(db *gorm.DB, job *entity.BulkRateJob) error {
+	return db.Model(job).Select("processed_rows", "failed_rows").Updates(job).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"shipping-gateway/internal/entity"
+)
+
+type CourierRepository struct {
+	Repository[entity.Courier]
+}
+
+func NewCourierRepository() *CourierRepository {
+	return &CourierRepository{}
+}
+
+// FindAllWithAliases returns every courier with its aliases loaded
+func (r *CourierRepository) FindAllWithAliases(db *gorm.DB) ([]entity.Courier, error) {
+	var couriers []entity.Courier
+	err := db.Preload("Aliases").Find(&couriers).Error
+	return couriers, err
+}
+
+// CreateIfMissing inserts the courier and its aliases unless a courier with the same code exists, even a deleted
+// one, and reports whether it was inserted
+func (r *CourierRepository) CreateIfMissing(db *gorm.DB, courier *entity.Courier) (bool, error) {
+	var count int64
+	if err := db.Unscoped().Model(&entity.Courier{}).Where("code = ?", courier.Code).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Omit("Aliases").Create(courier)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	if len(courier.Aliases) == 0 {
+		return true, nil
+	}
+	for i := range courier.Aliases {
+		courier.Aliases[i].CourierID = courier.ID
+	}
+	return true, db.Clauses(clause.OnConflict{DoNothing: true}).Create(&courier.Aliases).Error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type CourierServiceRepository struct {
+	Repository[entity.CourierService]
+}
+
+func NewCourierServiceRepository() *CourierServiceRepository {
+	return &CourierServiceRepository{}
+}
+
+// FindByCourierCodes returns the services of the given couriers
+func (r *CourierServiceRepository) FindByCourierCodes(db *gorm.DB, courierCodes []string) ([]entity.CourierService, error) {
+	var services []entity.CourierService
+	err := db.Where("courier_code IN ?", courierCodes).Find(&services).Error
+	return services, err
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type MerchantRepository struct {
+	Repository[entity.Merchant]
+}
+
+func NewMerchantRepository() *MerchantRepository {
+	return &MerchantRepository{}
+}
+
+// FindAll returns every merchant ordered by name
+func (r *MerchantRepository) FindAll(db *gorm.DB) ([]entity.Merchant, error) {
+	var merchants []entity.Merchant
+	err := db.Order("name ASC").Find(&merchants).Error
+	return merchants, err
+}
+
+// UpdateCredentials stores the credentials of a merchant without touching its other fields
+func (r *MerchantRepository) UpdateCredentials(db *gorm.DB, merchant *entity.Merchant) error {
+	return db.Model(merchant).Select("credentials").Updates(merchant).Error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type PricingRuleRepository struct {
+	Repository[entity.PricingRule]
+}
+
+func NewPricingRuleRepository() *PricingRuleRepository {
+	return &PricingRuleRepository{}
+}
+
+// FindEnabled returns the enabled platform rules followed by the enabled rules of the merchant, each ordered by priority.
+// Rules are applied in this order, so the merchant's own rules decide the final price.
+func (r *PricingRuleRepository) FindEnabled(db *gorm.DB, merchantID string) ([]entity.PricingRule, error) {
+	var rules []entity.PricingRule
+	err := db.Where("merchant_id IN (?, '') AND enabled = ?", merchantID, true).
+		Order("merchant_id <> '' ASC, priority ASC, id ASC").
+		Find(&rules).Error
+	return rules, err
+}
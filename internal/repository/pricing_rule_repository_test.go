@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	return db, mock
+}
+
+func TestPricingRuleRepositoryFindEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		merchantID string
+	}{
+		{"merchant gets platform and own rules", "merchant-1"},
+		{"platform gets platform rules", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectQuery(`WHERE \(merchant_id IN \(\?, ''\) AND enabled = \?\) AND .*deleted_at.* IS NULL ORDER BY merchant_id <> '' ASC, priority ASC, id ASC`).
+				WithArgs(tt.merchantID, true).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "merchant_id", "priority"}).
+					AddRow(2, "", 5).
+					AddRow(1, tt.merchantID, 1))
+
+			rules, err := NewPricingRuleRepository().FindEnabled(db, tt.merchantID)
+			if err != nil {
+				t.Fatalf("FindEnabled() error = %v", err)
+			}
+			if len(rules) != 2 || rules[0].ID != 2 || rules[1].ID != 1 {
+				t.Errorf("FindEnabled() = %+v, want the platform rule first", rules)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type QuoteRepository struct {
+	Repository[entity.Quote]
+}
+
+func NewQuoteRepository() *QuoteRepository {
+	return &QuoteRepository{}
+}
+
+// CreateInBatch inserts all quotes in a single statement
+func (r *QuoteRepository) CreateInBatch(db *gorm.DB, quotes []entity.Quote) error {
+	return db.Create(&quotes).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+)
+
+type ProvinceRepository struct {
+	Repository[entity.Province]
+}
+
+func NewProvinceRepository() *ProvinceRepository {
+	return &ProvinceRepository{}
+}
+
+type CityRepository struct {
+	Repository[entity.City]
+}
+
+func NewCityRepository() *CityRepository {
+	return &CityRepository{}
+}
+
+type DistrictRepository struct {
+	Repository[entity.District]
+}
+
+func NewDistrictRepository() *DistrictRepository {
+	return &DistrictRepository{}
+}
+
+type SubdistrictRepository struct {
+	Repository[entity.Subdistrict]
+}
+
+func NewSubdistrictRepository() *SubdistrictRepository {
+	return &SubdistrictRepository{}
+}
+
+// FindPageWithHierarchy returns up to limit subdistricts with an ID greater than afterID, ordered by ID,
+// with their district, city and province loaded
+func (r *SubdistrictRepository) FindPageWithHierarchy(db *gorm.DB, afterID uint, limit int) ([]entity.Subdistrict, error) {
+	var subdistricts []entity.Subdistrict
+	err := db.Preload("District.City.Province").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&subdistricts).Error
+	return subdistricts, err
+}
+
+// FindByIDWithHierarchy returns a subdistrict with its district, city and province loaded
+func (r *SubdistrictRepository) FindByIDWithHierarchy(db *gorm.DB, id uint) (*entity.Subdistrict, error) {
+	var subdistrict entity.Subdistrict
+	if err := db.Preload("District.City.Province").First(&subdistrict, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &subdistrict, nil
+}
+
+// FindAll returns every province ordered by name
+func (r *ProvinceRepository) FindAll(db *gorm.DB) ([]entity.Province, error) {
+	var provinces []entity.Province
+	err := db.Order("name ASC").Find(&provinces).Error
+	return provinces, err
+}
+
+// FindByProvinceID returns the cities of a province ordered by name
+func (r *CityRepository) FindByProvinceID(db *gorm.DB, provinceID uint) ([]entity.City, error) {
+	var cities []entity.City
+	err := db.Where("province_id = ?", provinceID).Order("name ASC").Find(&cities).Error
+	return cities, err
+}
+
+// FindByCityID returns the districts of a city ordered by name
+func (r *DistrictRepository) FindByCityID(db *gorm.DB, cityID uint) ([]entity.District, error) {
+	var districts []entity.District
+	err := db.Where("city_id = ?", cityID).Order("name ASC").Find(&districts).Error
+	return districts, err
+}
+
+// FindByDistrictID returns the subdistricts of a district ordered by name
+func (r *SubdistrictRepository) FindByDistrictID(db *gorm.DB, districtID uint) ([]entity.Subdistrict, error) {
+	var subdistricts []entity.Subdistrict
+	err := db.Where("district_id = ?", districtID).Order("name ASC").Find(&subdistricts).Error
+	return subdistricts, err
+}
+
+// FindNames returns the ID and name of every subdistrict
+func (r *SubdistrictRepository) FindNames(db *gorm.DB) ([]entity.Subdistrict, error) {
+	var subdistricts []entity.Subdistrict
+	err := db.Select("id", "name").Find(&subdistricts).Error
+	return subdistricts, err
+}
+
+// FindByPostalCodeWithHierarchy returns the subdistricts using a postal code with their district, city and province loaded
+func (r *SubdistrictRepository) FindByPostalCodeWithHierarchy(db *gorm.DB, postalCode string) ([]entity.Subdistrict, error) {
+	var subdistricts []entity.Subdistrict
+	err := db.Preload("District.City.Province").Where("postal_code = ?", postalCode).Find(&subdistricts).Error
+	return subdistricts, err
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Repository[T any] struct {
 	DB *gorm.DB
@@ -26,3 +29,8 @@ func (r *Repository[T]) FindByID(db *gorm.DB, id any) (*T, error) {
 	}
 	return &entity, nil
 }
+
+// UpsertInBatches inserts the entities in batches, updating every column of rows whose primary key already exists
+func (r *Repository[T]) UpsertInBatches(db *gorm.DB, entities []T, batchSize int) error {
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(entities, batchSize).Error
+}
@@ -14,9 +14,9 @@ func NewTrackingLogRepository() *TrackingLogRepository {
 	return &TrackingLogRepository{}
 }
 
-func (r *TrackingLogRepository) FindByWaybillAndCourierCode(db *gorm.DB, waybill, courierCode string) (*entity.ShipmentTrackingLog, error) {
+func (r *TrackingLogRepository) FindByWaybillAndCourierCode(db *gorm.DB, merchantID, waybill, courierCode string) (*entity.ShipmentTrackingLog, error) {
 	var log entity.ShipmentTrackingLog
-	if err := db.Where("waybill = ?", waybill).Where("courier_code = ?", courierCode).First(&log).Error; err != nil {
+	if err := db.Where("merchant_id = ?", merchantID).Where("waybill = ?", waybill).Where("courier_code = ?", courierCode).First(&log).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // No record found
 		}
@@ -26,7 +26,7 @@ func (r *TrackingLogRepository) FindByWaybillAndCourierCode(db *gorm.DB, waybill
 }
 
 func (r *TrackingLogRepository) CreateOrUpdate(db *gorm.DB, log *entity.ShipmentTrackingLog) error {
-	existingLog, err := r.FindByWaybillAndCourierCode(db, log.Waybill, log.CourierCode)
+	existingLog, err := r.FindByWaybillAndCourierCode(db, log.MerchantID, log.Waybill, log.CourierCode)
 	if err != nil {
 		return err
 	}
@@ -38,3 +38,14 @@ func (r *TrackingLogRepository) CreateOrUpdate(db *gorm.DB, log *entity.Shipment
 
 	return r.Create(db, log)
 }
+
+// CanonicalizeCourierCodes replaces courier codes stored in another spelling with the canonical code of the
+// courier alias they match, returning the number of updated logs
+func (r *TrackingLogRepository) CanonicalizeCourierCodes(db *gorm.DB) (int64, error) {
+	result := db.Exec(`UPDATE shipment_tracking_logs t
+		JOIN courier_aliases a ON a.alias = LOWER(t.courier_code)
+		JOIN couriers c ON c.id = a.courier_id
+		SET t.courier_code = c.code
+		WHERE t.courier_code <> c.code`)
+	return result.RowsAffected, result.Error
+}
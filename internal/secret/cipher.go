@@ -0,0 +1,124 @@
+package secret
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// legacyVersion values were sealed directly with a single key, before envelope encryption
+	legacyVersion = "v1"
+	// envelopeVersion values are sealed with a random data key, which is itself sealed with a master key
+	envelopeVersion = "v2"
+)
+
+// Cipher encrypts secrets such as provider credentials with envelope encryption. Every value is sealed with its
+// own random data key using AES-256-GCM, and the data key is stored next to it, sealed with the current master key.
+// Rotating the master key only requires re-encrypting values, the master keys themselves never leave the keyring.
+type Cipher struct {
+	keyring *Keyring
+}
+
+func NewCipher(keyring *Keyring) *Cipher {
+	return &Cipher{keyring: keyring}
+}
+
+// Encrypt seals the plaintext, as "v2:<master key id>:<base64 sealed data key>:<base64 sealed plaintext>"
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	kek, ok := c.keyring.keys[c.keyring.current]
+	if !ok {
+		return "", ErrNoMasterKey
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dek, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// the master key ID is authenticated with the data key so a value cannot be moved under another key
+	wrappedKey, err := seal(kek, dataKey, []byte(c.keyring.current))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dek, plaintext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{envelopeVersion, c.keyring.current,
+		base64.StdEncoding.EncodeToString(wrappedKey), base64.StdEncoding.EncodeToString(sealed)}, ":"), nil
+}
+
+// Decrypt opens a value returned by Encrypt. Values written before envelope encryption are opened with whichever
+// master key sealed them.
+func (c *Cipher) Decrypt(ciphertext string) ([]byte, error) {
+	parts := strings.Split(ciphertext, ":")
+	switch {
+	case len(parts) == 4 && parts[0] == envelopeVersion:
+		kek, ok := c.keyring.keys[parts[1]]
+		if !ok {
+			return nil, fmt.Errorf("master key %s is not in the keyring", parts[1])
+		}
+		wrappedKey, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("data key is not valid base64: %w", err)
+		}
+		dataKey, err := open(kek, wrappedKey, []byte(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("error opening data key: %w", err)
+		}
+		dek, err := newAEAD(dataKey)
+		if err != nil {
+			return nil, err
+		}
+		sealed, err := base64.StdEncoding.DecodeString(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("ciphertext is not valid base64: %w", err)
+		}
+		return open(dek, sealed, nil)
+
+	case len(parts) == 2 && parts[0] == legacyVersion:
+		sealed, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("ciphertext is not valid base64: %w", err)
+		}
+		for _, key := range c.keyring.keys {
+			if plaintext, err := open(key, sealed, nil); err == nil {
+				return plaintext, nil
+			}
+		}
+		return nil, errors.New("no master key opens the value")
+
+	default:
+		return nil, errors.New("unsupported ciphertext format")
+	}
+}
+
+// NeedsRotation reports whether a value is not sealed with the current master key yet
+func (c *Cipher) NeedsRotation(ciphertext string) bool {
+	return !strings.HasPrefix(ciphertext, envelopeVersion+":"+c.keyring.current+":")
+}
+
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
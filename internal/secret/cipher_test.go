@@ -0,0 +1,138 @@
+package secret
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustParseKeyring(t *testing.T, spec string) *Keyring {
+	t.Helper()
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	return keyring
+}
+
+// sealLegacy seals a value the way credentials were sealed before envelope encryption
+func sealLegacy(t *testing.T, key byte, plaintext string) string {
+	t.Helper()
+	aead, err := newAEAD([]byte(strings.Repeat(string(key), 32)))
+	if err != nil {
+		t.Fatalf("newAEAD() error = %v", err)
+	}
+	sealed, err := seal(aead, []byte(plaintext), nil)
+	if err != nil {
+		t.Fatalf("seal() error = %v", err)
+	}
+	return legacyVersion + ":" + base64.StdEncoding.EncodeToString(sealed)
+}
+
+func TestCipherEncryptDecrypt(t *testing.T) {
+	cipher := NewCipher(mustParseKeyring(t, testKey("k1", 'a')))
+
+	for _, plaintext := range []string{"biteship_live_key", "", strings.Repeat("x", 4096)} {
+		ciphertext, err := cipher.Encrypt([]byte(plaintext))
+		if err != nil {
+			t.Fatalf("Encrypt() error = %v", err)
+		}
+		if !strings.HasPrefix(ciphertext, "v2:k1:") {
+			t.Errorf("Encrypt() = %q, want a v2 value sealed with k1", ciphertext)
+		}
+		got, err := cipher.Decrypt(ciphertext)
+		if err != nil || string(got) != plaintext {
+			t.Errorf("Decrypt(Encrypt(%.20q)) = %.20q, %v", plaintext, got, err)
+		}
+	}
+
+	first, _ := cipher.Encrypt([]byte("same"))
+	second, _ := cipher.Encrypt([]byte("same"))
+	if first == second {
+		t.Errorf("Encrypt() returned the same value twice, want a fresh data key and nonce per value")
+	}
+}
+
+func TestCipherEncryptWithoutKey(t *testing.T) {
+	cipher := NewCipher(mustParseKeyring(t, ""))
+	if _, err := cipher.Encrypt([]byte("secret")); !errors.Is(err, ErrNoMasterKey) {
+		t.Errorf("Encrypt() without a master key error = %v, want ErrNoMasterKey", err)
+	}
+}
+
+func TestCipherRotation(t *testing.T) {
+	old := NewCipher(mustParseKeyring(t, testKey("k1", 'a')))
+	sealedWithOld, err := old.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated := NewCipher(mustParseKeyring(t, testKey("k2", 'b')+","+testKey("k1", 'a')))
+	if !rotated.NeedsRotation(sealedWithOld) {
+		t.Errorf("NeedsRotation() of a value sealed with k1 = false, want true once k2 is current")
+	}
+	plaintext, err := rotated.Decrypt(sealedWithOld)
+	if err != nil || string(plaintext) != "secret" {
+		t.Fatalf("Decrypt() of a value sealed with the previous key = %q, %v", plaintext, err)
+	}
+
+	resealed, err := rotated.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if rotated.NeedsRotation(resealed) || !strings.HasPrefix(resealed, "v2:k2:") {
+		t.Errorf("Encrypt() after rotation = %q, want a value sealed with k2", resealed)
+	}
+
+	// once the old key is removed only re-encrypted values can be opened
+	current := NewCipher(mustParseKeyring(t, testKey("k2", 'b')))
+	if _, err := current.Decrypt(sealedWithOld); err == nil {
+		t.Errorf("Decrypt() with the old key removed error = nil, want an error")
+	}
+	if plaintext, err := current.Decrypt(resealed); err != nil || string(plaintext) != "secret" {
+		t.Errorf("Decrypt() of the re-encrypted value = %q, %v", plaintext, err)
+	}
+}
+
+func TestCipherDecrypt(t *testing.T) {
+	cipher := NewCipher(mustParseKeyring(t, testKey("k2", 'b')+","+testKey("legacy", 'z')))
+	valid, err := cipher.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	parts := strings.Split(valid, ":")
+
+	// moving a data key under another master key must fail, as the key id is authenticated
+	movedKey := strings.Join([]string{parts[0], "legacy", parts[2], parts[3]}, ":")
+	tamperedData := []byte(parts[3])
+	tamperedData[len(tamperedData)/2] ^= 1
+
+	tests := []struct {
+		name       string
+		ciphertext string
+		want       string
+		wantErr    bool
+	}{
+		{"envelope", valid, "secret", false},
+		{"legacy value sealed with any master key", sealLegacy(t, 'z', "old secret"), "old secret", false},
+		{"legacy value sealed with an unknown key", sealLegacy(t, 'q', "old secret"), "", true},
+		{"unknown master key", strings.Join([]string{parts[0], "k9", parts[2], parts[3]}, ":"), "", true},
+		{"data key moved to another master key", movedKey, "", true},
+		{"tampered ciphertext", strings.Join([]string{parts[0], parts[1], parts[2], string(tamperedData)}, ":"), "", true},
+		{"unsupported version", "v3:" + parts[3], "", true},
+		{"plaintext", "secret", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cipher.Decrypt(tt.ciphertext)
+			if (err != nil) != tt.wantErr || string(got) != tt.want {
+				t.Errorf("Decrypt() = %q, %v, want %q with error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if !cipher.NeedsRotation(sealLegacy(t, 'z', "old secret")) {
+		t.Errorf("NeedsRotation() of a legacy value = false, want true")
+	}
+}
@@ -0,0 +1,111 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MasterKeyEnv holds the master keys directly
+	MasterKeyEnv = "SHIPPING_GATEWAY_MASTER_KEY"
+	// MasterKeyFileEnv points to a file holding the master keys, such as a mounted Kubernetes or Docker secret
+	MasterKeyFileEnv = "SHIPPING_GATEWAY_MASTER_KEY_FILE"
+)
+
+// ErrNoMasterKey is returned when encrypting without any master key configured
+var ErrNoMasterKey = errors.New("no master key configured, set " + MasterKeyEnv + " or " + MasterKeyFileEnv)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Keyring holds the master keys wrapping the data keys of encrypted values. The current key wraps new values,
+// older keys are kept so values wrapped before a rotation can still be decrypted until they are re-encrypted.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// LoadKeyring reads the master keys from the environment, or from the file the environment points to.
+// The keyring is empty when neither is set.
+func LoadKeyring() (*Keyring, error) {
+	if spec := os.Getenv(MasterKeyEnv); spec != "" {
+		return ParseKeyring(spec)
+	}
+	if path := os.Getenv(MasterKeyFileEnv); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading master key file: %w", err)
+		}
+		return ParseKeyring(string(content))
+	}
+	return &Keyring{keys: make(map[string]cipher.AEAD)}, nil
+}
+
+// ParseKeyring parses master keys written as "<key id>:<base64 32 byte key>", separated by commas or new lines.
+// The first key is the current one.
+func ParseKeyring(spec string) (*Keyring, error) {
+	keyring := &Keyring{keys: make(map[string]cipher.AEAD)}
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		id, encodedKey, ok := strings.Cut(entry, ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("master key must be written as <key id>:<base64 key>")
+		}
+		if _, exists := keyring.keys[id]; exists {
+			return nil, fmt.Errorf("master key %s is listed twice", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, fmt.Errorf("master key %s is not valid base64: %w", id, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %s: %w", id, err)
+		}
+		keyring.keys[id] = aead
+		if keyring.current == "" {
+			keyring.current = id
+		}
+	}
+	return keyring, nil
+}
+
+// GenerateKey returns a new random master key written the way ParseKeyring reads it
+func GenerateKey(id string) (string, error) {
+	if !keyIDPattern.MatchString(id) {
+		return "", fmt.Errorf("key id may only contain letters, digits, '-' and '_'")
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Current returns the ID of the key wrapping new values, empty when the keyring is empty
+func (k *Keyring) Current() string {
+	return k.current
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(id string, fill byte) string {
+	return id + ":" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32)))
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		wantCurrent string
+		wantKeys    int
+		wantErr     string
+	}{
+		{"single key", testKey("k1", 'a'), "k1", 1, ""},
+		{"first key is current", testKey("k2", 'b') + "," + testKey("k1", 'a'), "k2", 2, ""},
+		{"new lines and comments", "# rotated\n" + testKey("k2", 'b') + "\r\n\n" + testKey("k1", 'a') + "\n", "k2", 2, ""},
+		{"empty", "", "", 0, ""},
+		{"missing id", base64.StdEncoding.EncodeToString(make([]byte, 32)), "", 0, "must be written as"},
+		{"invalid id", strings.Replace(testKey("k1", 'a'), "k1", "k 1", 1), "", 0, "must be written as"},
+		{"duplicate id", testKey("k1", 'a') + "," + testKey("k1", 'b'), "", 0, "listed twice"},
+		{"invalid base64", "k1:not base64!", "", 0, "not valid base64"},
+		{"short key", "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 16)), "", 0, "must be 32 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := ParseKeyring(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseKeyring() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseKeyring() error = %v", err)
+			}
+			if keyring.Current() != tt.wantCurrent || len(keyring.keys) != tt.wantKeys {
+				t.Errorf("ParseKeyring() = current %q with %d keys, want %q with %d", keyring.Current(), len(keyring.keys),
+					tt.wantCurrent, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	t.Setenv(MasterKeyEnv, "")
+	t.Setenv(MasterKeyFileEnv, "")
+	keyring, err := LoadKeyring()
+	if err != nil || keyring.Current() != "" {
+		t.Fatalf("LoadKeyring() without keys = %q, %v, want an empty keyring", keyring.Current(), err)
+	}
+
+	t.Setenv(MasterKeyEnv, testKey("k1", 'a'))
+	if keyring, err = LoadKeyring(); err != nil || keyring.Current() != "k1" {
+		t.Fatalf("LoadKeyring() from the environment = %v, want k1", err)
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	spec, err := GenerateKey("k1")
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	keyring, err := ParseKeyring(spec)
+	if err != nil || keyring.Current() != "k1" {
+		t.Fatalf("ParseKeyring(GenerateKey()) = %v, want a keyring with k1", err)
+	}
+
+	if _, err := GenerateKey("k:1"); err == nil {
+		t.Errorf("GenerateKey() with an invalid id error = nil, want an error")
+	}
+}
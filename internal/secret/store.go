@@ -0,0 +1,96 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"strings"
+)
+
+const (
+	// SecretsFileEnv points to the encrypted secrets file, secrets.yaml in the working directory by default
+	SecretsFileEnv = "SHIPPING_GATEWAY_SECRETS_FILE"
+	// ReferencePrefix marks config values that name a secret of the store instead of holding the value itself
+	ReferencePrefix = "secret://"
+)
+
+// Store is a YAML file of named secrets, each value encrypted with the Cipher, so it can be committed or shipped
+// along the config while only holders of the master key can read it
+type Store struct {
+	path    string
+	secrets map[string]string
+}
+
+// SecretsFile returns the path of the secrets file
+func SecretsFile() string {
+	if path := os.Getenv(SecretsFileEnv); path != "" {
+		return path
+	}
+	return "secrets.yaml"
+}
+
+// LoadStore reads the secrets file. A missing file is an empty store.
+func LoadStore(path string) (*Store, error) {
+	store := &Store{path: path, secrets: make(map[string]string)}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("error reading secrets file %s: %w", path, err)
+	}
+
+	for name, value := range v.GetStringMapString("secrets") {
+		store.secrets[name] = value
+	}
+	return store, nil
+}
+
+// Get returns the encrypted value of a secret
+func (s *Store) Get(name string) (string, bool) {
+	value, ok := s.secrets[strings.ToLower(name)]
+	return value, ok
+}
+
+// Set stores the encrypted value of a secret, Save writes it to the file
+func (s *Store) Set(name, ciphertext string) {
+	s.secrets[strings.ToLower(name)] = ciphertext
+}
+
+// Names returns the name of every secret
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.secrets))
+	for name := range s.secrets {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *Store) Save() error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set("secrets", s.secrets)
+	if err := v.WriteConfigAs(s.path); err != nil {
+		return fmt.Errorf("error writing secrets file %s: %w", s.path, err)
+	}
+	return os.Chmod(s.path, 0o600)
+}
+
+// Resolve returns the plaintext of a secret:// reference
+func (s *Store) Resolve(cipher *Cipher, reference string) (string, error) {
+	name := strings.TrimPrefix(reference, ReferencePrefix)
+	ciphertext, ok := s.Get(name)
+	if !ok {
+		return "", fmt.Errorf("secret %s is not in %s", name, s.path)
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting secret %s: %w", name, err)
+	}
+	return string(plaintext), nil
+}
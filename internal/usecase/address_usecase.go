@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/internal/address"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"strconv"
+)
+
+// minAddressMatch is the lowest name similarity at which a parsed address is taken to be in a subdistrict
+const minAddressMatch = 0.6
+
+type AddressUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	SubdistrictRepo *repository.SubdistrictRepository
+}
+
+func NewAddressUseCase(db *gorm.DB, log *logrus.Logger, subdistrictRepo *repository.SubdistrictRepository) *AddressUseCase {
+	return &AddressUseCase{
+		DB:              db,
+		Log:             log,
+		SubdistrictRepo: subdistrictRepo,
+	}
+}
+
+func (uc *AddressUseCase) ParseAddress(ctx context.Context, raw string) (*model.ServiceResponse, *model.ParsedAddress) {
+	parsed, err := uc.Parse(ctx, raw)
+	if err != nil {
+		return model.DefaultError("failed to parse address", nil), nil
+	}
+	return model.Success(), parsed
+}
+
+// Parse reads a free-text address and checks its postal code against the area master data. Parts missing from
+// the address are completed from the matching subdistrict.
+func (uc *AddressUseCase) Parse(ctx context.Context, raw string) (*model.ParsedAddress, error) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	addr := address.Parse(raw)
+	parsed := &model.ParsedAddress{Normalized: address.Normalize(raw), Warnings: make([]string, 0)}
+
+	if addr.PostalCode == "" {
+		parsed.Warnings = append(parsed.Warnings, "address has no postal code")
+	} else {
+		subdistricts, err := uc.SubdistrictRepo.FindByPostalCodeWithHierarchy(uc.DB, addr.PostalCode)
+		if err != nil {
+			log.Errorf("Error finding subdistricts of postal code %s: %v", addr.PostalCode, err)
+			return nil, err
+		}
+
+		valid := len(subdistricts) > 0
+		parsed.PostalCodeValid = &valid
+		if !valid {
+			parsed.Warnings = append(parsed.Warnings, fmt.Sprintf("postal code %s is not in the area master data", addr.PostalCode))
+		} else if subdistrict := matchSubdistrict(addr, subdistricts); subdistrict != nil {
+			parsed.SubdistrictID = subdistrict.ID
+			completeAddress(&addr, subdistrict)
+		} else {
+			parsed.Warnings = append(parsed.Warnings, fmt.Sprintf("address does not match any subdistrict of postal code %s", addr.PostalCode))
+		}
+	}
+	if addr.District == "" && addr.Subdistrict == "" {
+		parsed.Warnings = append(parsed.Warnings, "address has no kelurahan or kecamatan")
+	}
+
+	parsed.Street = addr.Street
+	parsed.RT, parsed.RW = addr.RT, addr.RW
+	parsed.Subdistrict, parsed.District = addr.Subdistrict, addr.District
+	parsed.City, parsed.CityType, parsed.Province = addr.City, addr.CityType, addr.Province
+	parsed.PostalCode = addr.PostalCode
+	parsed.Query = addr.Query()
+	return parsed, nil
+}
+
+// ApplyAddress fills the empty location fields of a rate request from a free-text address
+func (uc *AddressUseCase) ApplyAddress(ctx context.Context, raw string, subdistrictID, postalCode, query *string) {
+	if raw == "" || (*subdistrictID != "" && *query != "") {
+		return
+	}
+
+	parsed, err := uc.Parse(ctx, raw)
+	if err != nil {
+		return
+	}
+	if *subdistrictID == "" && parsed.SubdistrictID > 0 {
+		*subdistrictID = strconv.FormatUint(uint64(parsed.SubdistrictID), 10)
+	}
+	if *postalCode == "" && parsed.PostalCodeValid != nil && *parsed.PostalCodeValid {
+		*postalCode = parsed.PostalCode
+	}
+	if *query == "" {
+		*query = parsed.Query
+	}
+}
+
+// matchSubdistrict returns the subdistrict of a postal code whose names best match the address. A postal code
+// used by a single subdistrict matches unless the address names another district.
+func matchSubdistrict(addr address.Address, subdistricts []entity.Subdistrict) *entity.Subdistrict {
+	var best *entity.Subdistrict
+	bestScore := 0.0
+	for i := range subdistricts {
+		subdistrict := &subdistricts[i]
+
+		score, weight := 0.0, 0.0
+		if addr.Subdistrict != "" {
+			score, weight = score+0.5*nameSimilarity(addr.Subdistrict, subdistrict.Name), weight+0.5
+		}
+		if addr.District != "" {
+			score, weight = score+0.3*nameSimilarity(addr.District, subdistrict.District.Name), weight+0.3
+		}
+		if addr.City != "" {
+			score, weight = score+0.2*nameSimilarity(addr.City, subdistrict.District.City.Name), weight+0.2
+		}
+		if weight > 0 {
+			score /= weight
+		} else if len(subdistricts) == 1 {
+			score = 1
+		}
+
+		if score > bestScore {
+			best, bestScore = subdistrict, score
+		}
+	}
+
+	if bestScore < minAddressMatch {
+		return nil
+	}
+	return best
+}
+
+// completeAddress fills the parts missing from an address with the names of its subdistrict
+func completeAddress(addr *address.Address, subdistrict *entity.Subdistrict) {
+	if addr.Subdistrict == "" {
+		addr.Subdistrict = subdistrict.Name
+	}
+	if addr.District == "" {
+		addr.District = subdistrict.District.Name
+	}
+	if addr.City == "" {
+		addr.City = subdistrict.District.City.Name
+	}
+	if addr.CityType == "" {
+		addr.CityType = subdistrict.District.City.Type
+	}
+	if addr.Province == "" {
+		addr.Province = subdistrict.District.City.Province.Name
+	}
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/model/converter"
+	"shipping-gateway/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognize
+const apiKeyPrefix = "sgw"
+
+// lastUsedInterval limits how often the last use of a client is written
+const lastUsedInterval = time.Minute
+
+type APIClientUseCase struct {
+	DB            *gorm.DB
+	Log           *logrus.Logger
+	ClientRepo    *repository.APIClientRepository
+	MerchantRepo  *repository.MerchantRepository
+	KeyPepper     string        // Secret mixed into the stored key hashes
+	RotationGrace time.Duration // How long the previous key keeps working after a rotation
+}
+
+func NewAPIClientUseCase(db *gorm.DB, log *logrus.Logger, clientRepo *repository.APIClientRepository,
+	merchantRepo *repository.MerchantRepository, keyPepper string, rotationGrace time.Duration) *APIClientUseCase {
+	return &APIClientUseCase{
+		DB:            db,
+		Log:           log,
+		ClientRepo:    clientRepo,
+		MerchantRepo:  merchantRepo,
+		KeyPepper:     keyPepper,
+		RotationGrace: rotationGrace,
+	}
+}
+
+// Authenticate returns the client owning an API key. Unknown keys are unauthorized, disabled clients are forbidden.
+func (uc *APIClientUseCase) Authenticate(ctx context.Context, apiKey string) (*model.ServiceResponse, *entity.APIClient) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	parts := strings.Split(apiKey, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix {
+		return model.Unauthorized("Invalid API key"), nil
+	}
+
+	client, err := uc.ClientRepo.FindByKeyPrefix(uc.DB, parts[1])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Unauthorized("Invalid API key"), nil
+	}
+	if err != nil {
+		log.Errorf("Error finding API client: %v", err)
+		return model.DefaultError("Failed to authenticate", nil), nil
+	}
+
+	hash := []byte(uc.hash(apiKey))
+	current := hmac.Equal(hash, []byte(client.KeyHash))
+	previous := client.PreviousKeyHash != "" && client.PreviousExpiresAt != nil && time.Now().Before(*client.PreviousExpiresAt) &&
+		hmac.Equal(hash, []byte(client.PreviousKeyHash))
+	if !current && !previous {
+		return model.Unauthorized("Invalid API key"), nil
+	}
+	if !client.Enabled {
+		return model.Forbidden("API client is disabled"), nil
+	}
+
+	if err = uc.ClientRepo.TouchLastUsed(uc.DB, client.ID, time.Now(), lastUsedInterval); err != nil {
+		log.Warnf("Error recording last use of API client %s: %v", client.ID, err)
+	}
+	return model.Success(), client
+}
+
+// CreateClient registers a client and issues its first API key. The key is only returned here.
+func (uc *APIClientUseCase) CreateClient(ctx context.Context, req model.APIClientCreateRequest) (*model.ServiceResponse, *model.APIClientResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	// admin routes are not scoped to a merchant, so a merchant client must not reach them whoever creates it
+	if req.MerchantID != "" && slices.Contains(req.Scopes, entity.ScopeAdmin) {
+		return model.UnprocessableEntity("Scope admin cannot be granted to a merchant client"), nil
+	}
+
+	if req.MerchantID != "" {
+		_, err := uc.MerchantRepo.FindByID(uc.DB, req.MerchantID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.UnprocessableEntity("Merchant not found"), nil
+		}
+		if err != nil {
+			log.Errorf("Error getting merchant %s: %v", req.MerchantID, err)
+			return model.DefaultError("Failed to create API client", nil), nil
+		}
+	}
+
+	prefix, apiKey, err := newAPIKey()
+	if err != nil {
+		log.Errorf("Error generating API key: %v", err)
+		return model.DefaultError("Failed to create API client", nil), nil
+	}
+
+	client := &entity.APIClient{
+		Name:         req.Name,
+		MerchantID:   req.MerchantID,
+		KeyPrefix:    prefix,
+		KeyHash:      uc.hash(apiKey),
+		Scopes:       strings.Join(req.Scopes, ","),
+		Enabled:      true,
+		RateLimit:    req.RateLimit,
+		MonthlyQuota: req.MonthlyQuota,
+	}
+	if err = uc.ClientRepo.Create(uc.DB, client); err != nil {
+		log.Errorf("Error creating API client: %v", err)
+		return model.DefaultError("Failed to create API client", nil), nil
+	}
+
+	resp := converter.APIClientToResponse(client)
+	resp.APIKey = apiKey
+	return &model.ServiceResponse{StatusCode: http.StatusCreated, Message: "API client created"}, &resp
+}
+
+func (uc *APIClientUseCase) ListClients(ctx context.Context) (*model.ServiceResponse, []model.APIClientResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	clients, err := uc.ClientRepo.FindAll(uc.DB)
+	if err != nil {
+		log.Errorf("Error listing API clients: %v", err)
+		return model.DefaultError("Failed to list API clients", nil), nil
+	}
+
+	resp := make([]model.APIClientResponse, 0, len(clients))
+	for i := range clients {
+		resp = append(resp, converter.APIClientToResponse(&clients[i]))
+	}
+	return model.Success(), resp
+}
+
+// UpdateClient changes the scopes or limits of a client, or enables and disables it
+func (uc *APIClientUseCase) UpdateClient(ctx context.Context, id string, req model.APIClientUpdateRequest) (*model.ServiceResponse, *model.APIClientResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, client := uc.findClient(ctx, id)
+	if client == nil {
+		return ucResp, nil
+	}
+
+	if req.Scopes != nil {
+		if client.MerchantID != "" && slices.Contains(req.Scopes, entity.ScopeAdmin) {
+			return model.UnprocessableEntity("Scope admin cannot be granted to a merchant client"), nil
+		}
+		client.Scopes = strings.Join(req.Scopes, ",")
+	}
+	if req.Enabled != nil {
+		client.Enabled = *req.Enabled
+	}
+	if req.RateLimit != nil {
+		client.RateLimit = *req.RateLimit
+	}
+	if req.MonthlyQuota != nil {
+		client.MonthlyQuota = *req.MonthlyQuota
+	}
+	if err := uc.ClientRepo.Update(uc.DB, client); err != nil {
+		log.Errorf("Error updating API client %s: %v", id, err)
+		return model.DefaultError("Failed to update API client", nil), nil
+	}
+
+	resp := converter.APIClientToResponse(client)
+	return model.Success(), &resp
+}
+
+// RotateKey issues a new API key for a client. The previous key keeps working for the rotation grace period
+// so the client can be redeployed without downtime.
+func (uc *APIClientUseCase) RotateKey(ctx context.Context, id string) (*model.ServiceResponse, *model.APIClientResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, client := uc.findClient(ctx, id)
+	if client == nil {
+		return ucResp, nil
+	}
+
+	secret, err := randomToken(24)
+	if err != nil {
+		log.Errorf("Error generating API key: %v", err)
+		return model.DefaultError("Failed to rotate API key", nil), nil
+	}
+	apiKey := fmt.Sprintf("%s_%s_%s", apiKeyPrefix, client.KeyPrefix, secret)
+
+	now := time.Now()
+	previousExpiresAt := now.Add(uc.RotationGrace)
+	client.PreviousKeyHash, client.PreviousExpiresAt = client.KeyHash, &previousExpiresAt
+	client.KeyHash = uc.hash(apiKey)
+	client.RotatedAt = &now
+	if err = uc.ClientRepo.Update(uc.DB, client); err != nil {
+		log.Errorf("Error rotating API key of client %s: %v", id, err)
+		return model.DefaultError("Failed to rotate API key", nil), nil
+	}
+
+	resp := converter.APIClientToResponse(client)
+	resp.APIKey = apiKey
+	return model.Success(), &resp
+}
+
+func (uc *APIClientUseCase) findClient(ctx context.Context, id string) (*model.ServiceResponse, *entity.APIClient) {
+	client, err := uc.ClientRepo.FindByID(uc.DB, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.NotFound("API client not found"), nil
+	}
+	if err != nil {
+		uc.Log.WithField("traceId", ctx.Value("traceId")).Errorf("Error getting API client %s: %v", id, err)
+		return model.DefaultError("Failed to get API client", nil), nil
+	}
+	return model.Success(), client
+}
+
+func (uc *APIClientUseCase) hash(apiKey string) string {
+	mac := hmac.New(sha256.New, []byte(uc.KeyPepper))
+	mac.Write([]byte(apiKey))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newAPIKey returns a new key and its public prefix. Keys look like sgw_<prefix>_<secret>.
+func newAPIKey() (string, string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix := hex.EncodeToString(b)
+
+	secret, err := randomToken(24)
+	if err != nil {
+		return "", "", err
+	}
+	return prefix, fmt.Sprintf("%s_%s_%s", apiKeyPrefix, prefix, secret), nil
+}
+
+// randomToken returns n random bytes encoded without characters used as key separators
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(base64.RawURLEncoding.EncodeToString(b), "_", "-"), nil
+}
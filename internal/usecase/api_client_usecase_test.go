@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestAPIClientUseCaseAuthenticate(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	uc := &APIClientUseCase{Log: log, ClientRepo: repository.NewAPIClientRepository(), KeyPepper: "test-pepper"}
+
+	currentKey, previousKey := "sgw_a1b2c3d4e5f6_current-secret", "sgw_a1b2c3d4e5f6_previous-secret"
+	inGrace, expired := time.Now().Add(time.Hour), time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name              string
+		apiKey            string
+		lookup            bool  // the key is well formed, so its client is looked up
+		noClient          bool  // no client has the key prefix
+		lookupErr         error // error of the lookup
+		enabled           bool
+		previousExpiresAt *time.Time
+		wantStatus        int
+	}{
+		{name: "empty key", apiKey: "", wantStatus: http.StatusUnauthorized},
+		{name: "missing secret", apiKey: "sgw_a1b2c3d4e5f6", wantStatus: http.StatusUnauthorized},
+		{name: "wrong prefix", apiKey: "key_a1b2c3d4e5f6_current-secret", wantStatus: http.StatusUnauthorized},
+		{name: "extra separator", apiKey: "sgw_a1b2c3d4e5f6_current_secret", wantStatus: http.StatusUnauthorized},
+		{name: "unknown client", apiKey: currentKey, lookup: true, noClient: true, wantStatus: http.StatusUnauthorized},
+		{name: "lookup error", apiKey: currentKey, lookup: true, lookupErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "current key", apiKey: currentKey, lookup: true, enabled: true, previousExpiresAt: &inGrace, wantStatus: http.StatusOK},
+		{name: "wrong secret", apiKey: "sgw_a1b2c3d4e5f6_guessed-secret", lookup: true, enabled: true, previousExpiresAt: &inGrace, wantStatus: http.StatusUnauthorized},
+		{name: "previous key within grace", apiKey: previousKey, lookup: true, enabled: true, previousExpiresAt: &inGrace, wantStatus: http.StatusOK},
+		{name: "previous key after grace", apiKey: previousKey, lookup: true, enabled: true, previousExpiresAt: &expired, wantStatus: http.StatusUnauthorized},
+		{name: "previous key without expiry", apiKey: previousKey, lookup: true, enabled: true, wantStatus: http.StatusUnauthorized},
+		{name: "disabled client", apiKey: currentKey, lookup: true, previousExpiresAt: &inGrace, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			uc.DB = db
+
+			if tt.lookup {
+				query := mock.ExpectQuery("FROM `api_clients` WHERE key_prefix = \\?").WithArgs("a1b2c3d4e5f6", 1)
+				rows := sqlmock.NewRows([]string{"id", "key_prefix", "key_hash", "previous_key_hash", "previous_expires_at", "enabled"})
+				switch {
+				case tt.lookupErr != nil:
+					query.WillReturnError(tt.lookupErr)
+				case tt.noClient:
+					query.WillReturnRows(rows)
+				default:
+					var previousExpiresAt driver.Value
+					if tt.previousExpiresAt != nil {
+						previousExpiresAt = *tt.previousExpiresAt
+					}
+					query.WillReturnRows(rows.AddRow("client-1", "a1b2c3d4e5f6", uc.hash(currentKey), uc.hash(previousKey),
+						previousExpiresAt, tt.enabled))
+				}
+			}
+			if tt.wantStatus == http.StatusOK {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `api_clients` SET `last_used_at`=\\?").
+					WithArgs(sqlmock.AnyArg(), "client-1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			ucResp, client := uc.Authenticate(context.Background(), tt.apiKey)
+			if ucResp.StatusCode != tt.wantStatus {
+				t.Errorf("Authenticate() status = %d, want %d", ucResp.StatusCode, tt.wantStatus)
+			}
+			if (client != nil) != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("Authenticate() client = %+v, want one only when authenticated", client)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	prefix, apiKey, err := newAPIKey()
+	if err != nil {
+		t.Fatalf("newAPIKey() error = %v", err)
+	}
+	if len(prefix) != 12 || apiKey[:len(apiKeyPrefix)+len(prefix)+2] != apiKeyPrefix+"_"+prefix+"_" {
+		t.Errorf("newAPIKey() = %q, %q, want sgw_<prefix>_<secret>", prefix, apiKey)
+	}
+	for i := 0; i < 100; i++ {
+		secret, _ := randomToken(24)
+		for _, c := range secret {
+			if c == '_' {
+				t.Fatalf("randomToken() = %q, contains the key separator", secret)
+			}
+		}
+	}
+}
+
+func TestAPIClientUseCaseCreateClientRefusesAdminMerchantClient(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	db, mock := newMockDB(t)
+	uc := &APIClientUseCase{DB: db, Log: log, ClientRepo: repository.NewAPIClientRepository(),
+		MerchantRepo: repository.NewMerchantRepository(), KeyPepper: "test-pepper"}
+
+	req := model.APIClientCreateRequest{Name: "shop", MerchantID: "merchant-1", Scopes: []string{entity.ScopeRates, entity.ScopeAdmin}}
+	ucResp, client := uc.CreateClient(context.Background(), req)
+	if ucResp.StatusCode != http.StatusUnprocessableEntity || client != nil {
+		t.Errorf("CreateClient() = %d, %v, want %d and no client", ucResp.StatusCode, client, http.StatusUnprocessableEntity)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("CreateClient() queried the database: %v", err)
+	}
+}
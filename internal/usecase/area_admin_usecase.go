@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/model/converter"
+	"shipping-gateway/internal/repository"
+)
+
+// AreaAdminUseCase lets operators review and correct the mapping of subdistricts to provider areas.
+// Every change is recorded in the area audit trail and drops the cached mapping.
+type AreaAdminUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	Redis           *redis.Client
+	AreaRepo        *repository.AreaRepository
+	AuditRepo       *repository.AreaAuditRepository
+	SubdistrictRepo *repository.SubdistrictRepository
+	AreaImportUC    *AreaImportUseCase
+	MinScore        float64 // Minimum match score of a confident mapping
+}
+
+func NewAreaAdminUseCase(db *gorm.DB, log *logrus.Logger, redis *redis.Client, areaRepo *repository.AreaRepository,
+	auditRepo *repository.AreaAuditRepository, subdistrictRepo *repository.SubdistrictRepository,
+	areaImportUC *AreaImportUseCase, minScore float64) *AreaAdminUseCase {
+	return &AreaAdminUseCase{
+		DB:              db,
+		Log:             log,
+		Redis:           redis,
+		AreaRepo:        areaRepo,
+		AuditRepo:       auditRepo,
+		SubdistrictRepo: subdistrictRepo,
+		AreaImportUC:    areaImportUC,
+		MinScore:        minScore,
+	}
+}
+
+func (uc *AreaAdminUseCase) ListAreas(ctx context.Context, filter model.AreaFilter) (*model.ServiceResponse, []model.AreaResponse, *model.PaginationResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	filter.MinScore = uc.MinScore
+	areas, total, err := uc.AreaRepo.FindPage(uc.DB, filter)
+	if err != nil {
+		log.Errorf("Error listing areas: %v", err)
+		return model.DefaultError("failed to list areas", nil), nil, nil
+	}
+
+	resp := make([]model.AreaResponse, 0, len(areas))
+	for i := range areas {
+		resp = append(resp, converter.AreaToResponse(&areas[i]))
+	}
+
+	return model.Success(), resp, &model.PaginationResponse{
+		Page:       filter.Page,
+		Size:       filter.Size,
+		Total:      int(total),
+		TotalPages: int(math.Ceil(float64(total) / float64(filter.Size))),
+	}
+}
+
+func (uc *AreaAdminUseCase) GetArea(ctx context.Context, id uint) (*model.ServiceResponse, *model.AreaResponse) {
+	ucResp, area := uc.findArea(ctx, id)
+	if area == nil {
+		return ucResp, nil
+	}
+
+	resp := converter.AreaToResponse(area)
+	return model.Success(), &resp
+}
+
+func (uc *AreaAdminUseCase) GetAreaAudits(ctx context.Context, id uint) (*model.ServiceResponse, []model.AreaAuditResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	audits, err := uc.AuditRepo.FindByAreaID(uc.DB, id)
+	if err != nil {
+		log.Errorf("Error getting audit trail of area %d: %v", id, err)
+		return model.DefaultError("failed to get area audit trail", nil), nil
+	}
+
+	resp := make([]model.AreaAuditResponse, 0, len(audits))
+	for i := range audits {
+		audit, err := converter.AreaAuditToResponse(&audits[i])
+		if err != nil {
+			log.Errorf("Error converting audit %d: %v", audits[i].ID, err)
+			return model.DefaultError("failed to read area audit trail", nil), nil
+		}
+		resp = append(resp, *audit)
+	}
+
+	return model.Success(), resp
+}
+
+// CreateArea maps a subdistrict to a provider area by hand
+func (uc *AreaAdminUseCase) CreateArea(ctx context.Context, req model.AreaCreateRequest) (*model.ServiceResponse, *model.AreaResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	existing, err := uc.AreaRepo.FindBySubdistrictIDs(uc.DB, "biteship", []uint{req.OriginalSubdistrictID})
+	if err != nil {
+		log.Errorf("Error checking existing area of subdistrict %d: %v", req.OriginalSubdistrictID, err)
+		return model.DefaultError("failed to create area", nil), nil
+	}
+	if len(existing) > 0 {
+		return model.Conflict(fmt.Sprintf("subdistrict %d is already mapped by area %d", req.OriginalSubdistrictID, existing[0].ID)), nil
+	}
+
+	area := &entity.Area{
+		OriginalSubdistrictID: req.OriginalSubdistrictID,
+		OriginalPostalCode:    req.OriginalPostalCode,
+		Description:           req.Description,
+		ExternalSource:        "biteship",
+		ExternalID:            req.ExternalID,
+		Latitude:              req.Latitude,
+		Longitude:             req.Longitude,
+		MatchScore:            1,
+	}
+
+	err = uc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := uc.AreaRepo.Create(tx, area); err != nil {
+			return err
+		}
+		return uc.audit(tx, area.ID, entity.AreaAuditCreate, req.Actor, req.Reason, nil, area)
+	})
+	if err != nil {
+		log.Errorf("Error creating area for subdistrict %d: %v", req.OriginalSubdistrictID, err)
+		return model.DefaultError("failed to create area", nil), nil
+	}
+	uc.invalidateCache(ctx, area)
+
+	resp := converter.AreaToResponse(area)
+	return &model.ServiceResponse{StatusCode: http.StatusCreated, Message: "Area created"}, &resp
+}
+
+// OverrideArea points an area to another provider area. Overridden mappings are trusted, so the importer
+// will not re-resolve them unless forced.
+func (uc *AreaAdminUseCase) OverrideArea(ctx context.Context, id uint, req model.AreaOverrideRequest) (*model.ServiceResponse, *model.AreaResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, area := uc.findArea(ctx, id)
+	if area == nil {
+		return ucResp, nil
+	}
+	before := *area
+
+	if area.ExternalID != req.ExternalID {
+		// the stored provider details describe the previous area
+		area.ExternalInfo = ""
+	}
+	area.ExternalID = req.ExternalID
+	if req.Description != "" {
+		area.Description = req.Description
+	}
+	if req.Latitude != nil {
+		area.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		area.Longitude = *req.Longitude
+	}
+	area.MatchScore = 1
+	area.NeedsReview = false
+
+	err := uc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := uc.AreaRepo.Update(tx, area); err != nil {
+			return err
+		}
+		return uc.audit(tx, area.ID, entity.AreaAuditOverride, req.Actor, req.Reason, &before, area)
+	})
+	if err != nil {
+		log.Errorf("Error overriding area %d: %v", id, err)
+		return model.DefaultError("failed to update area", nil), nil
+	}
+	uc.invalidateCache(ctx, &before)
+	uc.invalidateCache(ctx, area)
+
+	resp := converter.AreaToResponse(area)
+	return model.Success(), &resp
+}
+
+// ReResolveArea searches the provider again for the subdistrict of an area and saves the best match.
+// The subdistrict must be in the imported master data so the candidates can be scored.
+func (uc *AreaAdminUseCase) ReResolveArea(ctx context.Context, id uint, req model.AreaChangeRequest) (*model.ServiceResponse, *model.AreaResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, area := uc.findArea(ctx, id)
+	if area == nil {
+		return ucResp, nil
+	}
+	before := *area
+
+	subdistrict, err := uc.SubdistrictRepo.FindByIDWithHierarchy(uc.DB, area.OriginalSubdistrictID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UnprocessableEntity(fmt.Sprintf("subdistrict %d is not in the area master data, override the external_id instead",
+			area.OriginalSubdistrictID)), nil
+	}
+	if err != nil {
+		log.Errorf("Error getting subdistrict %d: %v", area.OriginalSubdistrictID, err)
+		return model.DefaultError("failed to re-resolve area", nil), nil
+	}
+
+	resolved, err := uc.AreaImportUC.ResolveSubdistrict(ctx, *subdistrict, uc.MinScore)
+	if err != nil {
+		log.Errorf("Error re-resolving area %d: %v", id, err)
+		return model.UnprocessableEntity(fmt.Sprintf("failed to re-resolve area: %v", err)), nil
+	}
+
+	if err = uc.audit(uc.DB, resolved.ID, entity.AreaAuditReResolve, req.Actor, req.Reason, &before, resolved); err != nil {
+		log.Errorf("Error recording audit of area %d: %v", id, err)
+	}
+	uc.invalidateCache(ctx, &before)
+	uc.invalidateCache(ctx, resolved)
+
+	resp := converter.AreaToResponse(resolved)
+	return model.Success(), &resp
+}
+
+// DeleteArea removes an area so the next lookup of its subdistrict searches the provider again
+func (uc *AreaAdminUseCase) DeleteArea(ctx context.Context, id uint, req model.AreaChangeRequest) *model.ServiceResponse {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, area := uc.findArea(ctx, id)
+	if area == nil {
+		return ucResp
+	}
+
+	err := uc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := uc.AreaRepo.Delete(tx, area); err != nil {
+			return err
+		}
+		return uc.audit(tx, area.ID, entity.AreaAuditDelete, req.Actor, req.Reason, area, nil)
+	})
+	if err != nil {
+		log.Errorf("Error deleting area %d: %v", id, err)
+		return model.DefaultError("failed to delete area", nil)
+	}
+	uc.invalidateCache(ctx, area)
+
+	return model.Success()
+}
+
+func (uc *AreaAdminUseCase) findArea(ctx context.Context, id uint) (*model.ServiceResponse, *entity.Area) {
+	area, err := uc.AreaRepo.FindByID(uc.DB, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.NotFound(fmt.Sprintf("area %d not found", id)), nil
+	}
+	if err != nil {
+		uc.Log.WithField("traceId", ctx.Value("traceId")).Errorf("Error getting area %d: %v", id, err)
+		return model.DefaultError("failed to get area", nil), nil
+	}
+	return model.Success(), area
+}
+
+func (uc *AreaAdminUseCase) audit(db *gorm.DB, areaID uint, action, actor, reason string, before, after *entity.Area) error {
+	audit := &entity.AreaAudit{AreaID: areaID, Action: action, Actor: actor, Reason: reason}
+	if before != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		audit.Before = string(b)
+	}
+	if after != nil {
+		a, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		audit.After = string(a)
+	}
+	return uc.AuditRepo.Create(db, audit)
+}
+
+// invalidateCache drops every key FindArea may have cached the area under, since lookups can be made
+// by subdistrict ID, postal code or both, and the region list showing the mapping
+func (uc *AreaAdminUseCase) invalidateCache(ctx context.Context, area *entity.Area) {
+	keys := []string{fmt.Sprintf("area::%d::%s", area.OriginalSubdistrictID, area.OriginalPostalCode)}
+	if area.OriginalSubdistrictID > 0 {
+		keys = append(keys, fmt.Sprintf("area::%d::", area.OriginalSubdistrictID))
+	}
+	if area.OriginalPostalCode != "" {
+		keys = append(keys, fmt.Sprintf("area::::%s", area.OriginalPostalCode))
+	}
+	if subdistrict, err := uc.SubdistrictRepo.FindByID(uc.DB, area.OriginalSubdistrictID); err == nil {
+		keys = append(keys, SubdistrictCacheKey(subdistrict.DistrictID))
+	}
+
+	if err := uc.Redis.Del(ctx, keys...).Err(); err != nil {
+		uc.Log.WithField("traceId", ctx.Value("traceId")).Errorf("Error deleting area cache %v: %v", keys, err)
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import "strings"
+
+// defaultAreaAliases expands common Indonesian region abbreviations found in user queries.
+// Keys are single normalized words, values are the full region name they stand for.
+var defaultAreaAliases = map[string]string{
+	// Jakarta
+	"jkt":    "jakarta",
+	"dki":    "jakarta",
+	"jakpus": "jakarta pusat",
+	"jakut":  "jakarta utara",
+	"jakbar": "jakarta barat",
+	"jaksel": "jakarta selatan",
+	"jaktim": "jakarta timur",
+	"jkp":    "jakarta pusat",
+	"jku":    "jakarta utara",
+	"jkb":    "jakarta barat",
+	"jks":    "jakarta selatan",
+
+	// Provinces
+	"jabar":      "jawa barat",
+	"jateng":     "jawa tengah",
+	"jatim":      "jawa timur",
+	"diy":        "yogyakarta",
+	"jogja":      "yogyakarta",
+	"jogjakarta": "yogyakarta",
+	"yogya":      "yogyakarta",
+	"sumut":      "sumatera utara",
+	"sumbar":     "sumatera barat",
+	"sumsel":     "sumatera selatan",
+	"kalbar":     "kalimantan barat",
+	"kalteng":    "kalimantan tengah",
+	"kalsel":     "kalimantan selatan",
+	"kaltim":     "kalimantan timur",
+	"kaltara":    "kalimantan utara",
+	"sulut":      "sulawesi utara",
+	"sulteng":    "sulawesi tengah",
+	"sulsel":     "sulawesi selatan",
+	"sultra":     "sulawesi tenggara",
+	"sulbar":     "sulawesi barat",
+	"ntb":        "nusa tenggara barat",
+	"ntt":        "nusa tenggara timur",
+	"babel":      "bangka belitung",
+	"kepri":      "kepulauan riau",
+
+	// Cities
+	"bdg":     "bandung",
+	"bks":     "bekasi",
+	"bgr":     "bogor",
+	"dpk":     "depok",
+	"tng":     "tangerang",
+	"tangsel": "tangerang selatan",
+	"sby":     "surabaya",
+	"smg":     "semarang",
+	"mdn":     "medan",
+	"mks":     "makassar",
+	"plg":     "palembang",
+	"dps":     "denpasar",
+	"mlg":     "malang",
+	"solo":    "surakarta",
+
+	// Directions
+	"sel": "selatan",
+	"utr": "utara",
+	"bar": "barat",
+	"tim": "timur",
+	"pst": "pusat",
+	"tgh": "tengah",
+}
+
+// expandAreaAliases replaces abbreviated words of a normalized query with their full names
+func expandAreaAliases(tokens []string, aliases map[string]string) []string {
+	expanded := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if full, ok := aliases[token]; ok {
+			expanded = append(expanded, strings.Fields(full)...)
+			continue
+		}
+		expanded = append(expanded, token)
+	}
+	return expanded
+}
@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// areaImportColumns are the columns the master data CSV must have, in any order. city_type is optional and
+// derived from the city name prefix ("Kota" or "Kabupaten") when missing.
+var areaImportColumns = []string{"province_id", "province_name", "city_id", "city_name", "district_id", "district_name",
+	"subdistrict_id", "subdistrict_name", "postal_code"}
+
+// Weights of each level when scoring how well a provider area matches a subdistrict
+const (
+	matchWeightPostalCode = 0.4
+	matchWeightDistrict   = 0.3
+	matchWeightCity       = 0.2
+	matchWeightProvince   = 0.1
+)
+
+type AreaImportUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	BiteshipClient  *biteship.Client
+	Redis           *redis.Client
+	AreaRepo        *repository.AreaRepository
+	ProvinceRepo    *repository.ProvinceRepository
+	CityRepo        *repository.CityRepository
+	DistrictRepo    *repository.DistrictRepository
+	SubdistrictRepo *repository.SubdistrictRepository
+}
+
+func NewAreaImportUseCase(db *gorm.DB, log *logrus.Logger, bs *biteship.Client, redis *redis.Client, areaRepo *repository.AreaRepository,
+	provinceRepo *repository.ProvinceRepository, cityRepo *repository.CityRepository, districtRepo *repository.DistrictRepository,
+	subdistrictRepo *repository.SubdistrictRepository) *AreaImportUseCase {
+	return &AreaImportUseCase{
+		DB:              db,
+		Log:             log,
+		BiteshipClient:  bs,
+		Redis:           redis,
+		AreaRepo:        areaRepo,
+		ProvinceRepo:    provinceRepo,
+		CityRepo:        cityRepo,
+		DistrictRepo:    districtRepo,
+		SubdistrictRepo: subdistrictRepo,
+	}
+}
+
+type AreaImportStats struct {
+	Provinces    int
+	Cities       int
+	Districts    int
+	Subdistricts int
+}
+
+// ImportCSV loads the province, city, district and subdistrict hierarchy from a master data CSV with one
+// subdistrict per line. Existing rows are updated in place, so the import can be re-run on a newer dataset.
+func (uc *AreaImportUseCase) ImportCSV(ctx context.Context, r io.Reader, batchSize int) (*AreaImportStats, error) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range areaImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header must contain column '%s'", name)
+		}
+	}
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	// parents of the subdistricts in the current batch, deduplicated as the same parent appears on many lines
+	provinces := make(map[uint]entity.Province)
+	cities := make(map[uint]entity.City)
+	districts := make(map[uint]entity.District)
+	subdistricts := make([]entity.Subdistrict, 0, batchSize)
+	seen := map[string]map[uint]bool{"province": {}, "city": {}, "district": {}}
+	stats := &AreaImportStats{}
+
+	flush := func() error {
+		if len(subdistricts) == 0 {
+			return nil
+		}
+
+		// parents are saved first, as the foreign keys of the subdistricts need them
+		if err := uc.ProvinceRepo.UpsertInBatches(uc.DB, mapValues(provinces), batchSize); err != nil {
+			return fmt.Errorf("failed to save provinces: %w", err)
+		}
+		if err := uc.CityRepo.UpsertInBatches(uc.DB, mapValues(cities), batchSize); err != nil {
+			return fmt.Errorf("failed to save cities: %w", err)
+		}
+		if err := uc.DistrictRepo.UpsertInBatches(uc.DB, mapValues(districts), batchSize); err != nil {
+			return fmt.Errorf("failed to save districts: %w", err)
+		}
+		clear(provinces)
+		clear(cities)
+		clear(districts)
+
+		if err := uc.SubdistrictRepo.UpsertInBatches(uc.DB, subdistricts, batchSize); err != nil {
+			return fmt.Errorf("failed to save subdistricts: %w", err)
+		}
+		stats.Subdistricts += len(subdistricts)
+		subdistricts = subdistricts[:0]
+		log.Infof("Imported %d subdistricts", stats.Subdistricts)
+		return nil
+	}
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		ids := make(map[string]uint)
+		for _, column := range []string{"province_id", "city_id", "district_id", "subdistrict_id"} {
+			id, err := strconv.ParseUint(strings.ReplaceAll(value(record, column), ".", ""), 10, 64)
+			if err != nil || id == 0 {
+				return nil, fmt.Errorf("line %d: column '%s' must be a positive number", line, column)
+			}
+			ids[column] = uint(id)
+		}
+
+		cityType, cityName := splitCityType(value(record, "city_type"), value(record, "city_name"))
+		provinces[ids["province_id"]] = entity.Province{ID: ids["province_id"], Name: value(record, "province_name")}
+		cities[ids["city_id"]] = entity.City{ID: ids["city_id"], ProvinceID: ids["province_id"], Type: cityType, Name: cityName}
+		districts[ids["district_id"]] = entity.District{ID: ids["district_id"], CityID: ids["city_id"], Name: value(record, "district_name")}
+		seen["province"][ids["province_id"]] = true
+		seen["city"][ids["city_id"]] = true
+		seen["district"][ids["district_id"]] = true
+		subdistricts = append(subdistricts, entity.Subdistrict{
+			ID:         ids["subdistrict_id"],
+			DistrictID: ids["district_id"],
+			Name:       value(record, "subdistrict_name"),
+			PostalCode: value(record, "postal_code"),
+		})
+
+		if len(subdistricts) >= batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	stats.Provinces, stats.Cities, stats.Districts = len(seen["province"]), len(seen["city"]), len(seen["district"])
+	if err := ClearRegionCache(ctx, uc.Redis); err != nil {
+		log.Errorf("Error clearing region cache: %v", err)
+	}
+	return stats, nil
+}
+
+type AreaResolveOptions struct {
+	MinScore  float64       // Matches scoring below this are flagged for manual review
+	Interval  time.Duration // Minimum delay between two provider searches
+	Force     bool          // Resolve subdistricts again even when they already have a confident mapping
+	BatchSize int           // Number of subdistricts loaded per page
+}
+
+type AreaResolveStats struct {
+	Resolved      int // Subdistricts mapped with a confident match
+	LowConfidence int // Subdistricts mapped but flagged for manual review
+	Failed        int // Subdistricts without any provider match
+	Skipped       int // Subdistricts that already had a confident mapping
+}
+
+// ResolveSubdistricts maps every imported subdistrict to the provider area that matches it best and records the
+// match score. Matches below MinScore are still saved but flagged for manual review.
+func (uc *AreaImportUseCase) ResolveSubdistricts(ctx context.Context, options AreaResolveOptions) (*AreaResolveStats, error) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+	stats := &AreaResolveStats{}
+	limiter := time.NewTicker(max(options.Interval, time.Millisecond))
+	defer limiter.Stop()
+
+	var afterID uint
+	for {
+		subdistricts, err := uc.SubdistrictRepo.FindPageWithHierarchy(uc.DB, afterID, options.BatchSize)
+		if err != nil {
+			return stats, fmt.Errorf("failed to load subdistricts: %w", err)
+		}
+		if len(subdistricts) == 0 {
+			return stats, nil
+		}
+		afterID = subdistricts[len(subdistricts)-1].ID
+
+		ids := make([]uint, 0, len(subdistricts))
+		for _, subdistrict := range subdistricts {
+			ids = append(ids, subdistrict.ID)
+		}
+		existing, err := uc.AreaRepo.FindBySubdistrictIDs(uc.DB, "biteship", ids)
+		if err != nil {
+			return stats, fmt.Errorf("failed to load existing areas: %w", err)
+		}
+		confident := make(map[uint]bool)
+		for _, area := range existing {
+			confident[area.OriginalSubdistrictID] = !area.NeedsReview && area.MatchScore >= options.MinScore
+		}
+
+		for _, subdistrict := range subdistricts {
+			if confident[subdistrict.ID] && !options.Force {
+				stats.Skipped++
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			case <-limiter.C:
+			}
+
+			area, err := uc.ResolveSubdistrict(ctx, subdistrict, options.MinScore)
+			if err != nil {
+				log.Warnf("Failed to resolve subdistrict %d (%s): %v", subdistrict.ID, subdistrict.Name, err)
+				stats.Failed++
+				continue
+			}
+			if area.NeedsReview {
+				stats.LowConfidence++
+			} else {
+				stats.Resolved++
+			}
+		}
+		log.Infof("Resolved up to subdistrict %d: %+v", afterID, *stats)
+	}
+}
+
+// ResolveSubdistrict searches the provider by postal code, falling back to the district and city names when no
+// candidate scores at least minScore, and saves the best match as the area of the subdistrict.
+// The subdistrict must have its district, city and province loaded.
+func (uc *AreaImportUseCase) ResolveSubdistrict(ctx context.Context, subdistrict entity.Subdistrict, minScore float64) (*entity.Area, error) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	best, bestScore := uc.searchBestMatch(ctx, subdistrict.PostalCode, subdistrict)
+	if bestScore < minScore {
+		query := fmt.Sprintf("%s, %s", subdistrict.District.Name, subdistrict.District.City.Name)
+		if candidate, score := uc.searchBestMatch(ctx, query, subdistrict); score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no provider area found")
+	}
+
+	info, err := json.Marshal(best)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal provider area: %w", err)
+	}
+
+	area := &entity.Area{
+		OriginalSubdistrictID: subdistrict.ID,
+		OriginalPostalCode:    subdistrict.PostalCode,
+		Description:           best.Name,
+		ExternalSource:        "biteship",
+		ExternalID:            best.ID,
+		ExternalInfo:          string(info),
+		Latitude:              best.Latitude,
+		Longitude:             best.Longitude,
+		MatchScore:            bestScore,
+		NeedsReview:           bestScore < minScore,
+	}
+	if err := uc.AreaRepo.SaveOrCreate(uc.DB, area); err != nil {
+		return nil, fmt.Errorf("failed to save area: %w", err)
+	}
+
+	// drop any cached mapping so FindArea and the region lists pick up the new one
+	rdsKeys := []string{fmt.Sprintf("area::%d::%s", subdistrict.ID, subdistrict.PostalCode), SubdistrictCacheKey(subdistrict.DistrictID)}
+	if err := uc.Redis.Del(ctx, rdsKeys...).Err(); err != nil {
+		log.Errorf("Error deleting area cache %v: %v", rdsKeys, err)
+	}
+
+	return area, nil
+}
+
+// searchBestMatch returns the provider area of the search results that best matches the subdistrict
+func (uc *AreaImportUseCase) searchBestMatch(ctx context.Context, query string, subdistrict entity.Subdistrict) (*biteship.Area, float64) {
+	if query == "" {
+		return nil, 0
+	}
+
+	resp, errResp := uc.BiteshipClient.SearchAreas(ctx, query)
+	if errResp != nil || resp == nil {
+		return nil, 0
+	}
+	return bestAreaMatch(resp.Areas, subdistrict)
+}
+
+// bestAreaMatch returns the provider area that best matches the subdistrict, the first one on equal scores
+func bestAreaMatch(areas []biteship.Area, subdistrict entity.Subdistrict) (*biteship.Area, float64) {
+	var best *biteship.Area
+	bestScore := 0.0
+	for i := range areas {
+		if score := scoreAreaMatch(areas[i], subdistrict); best == nil || score > bestScore {
+			best, bestScore = &areas[i], score
+		}
+	}
+	return best, bestScore
+}
+
+// scoreAreaMatch rates from 0 to 1 how well a provider area matches a subdistrict, weighing the postal code
+// and the similarity of the district, city and province names
+func scoreAreaMatch(area biteship.Area, subdistrict entity.Subdistrict) float64 {
+	score := 0.0
+	if strconv.Itoa(area.PostalCode) == subdistrict.PostalCode {
+		score += matchWeightPostalCode
+	}
+	score += matchWeightDistrict * nameSimilarity(area.AdministrativeDivisionLevel3Name, subdistrict.District.Name)
+	score += matchWeightCity * nameSimilarity(area.AdministrativeDivisionLevel2Name, subdistrict.District.City.Name)
+	score += matchWeightProvince * nameSimilarity(area.AdministrativeDivisionLevel1Name, subdistrict.District.City.Province.Name)
+	return float64(int(score*10000+0.5)) / 10000
+}
+
+// splitCityType separates "Kota" or "Kabupaten" from a city name when the type is not given separately
+func splitCityType(cityType, name string) (string, string) {
+	if cityType != "" {
+		return cityType, name
+	}
+
+	lower := strings.ToLower(name)
+	for _, prefix := range []struct{ prefix, cityType string }{
+		{"kabupaten ", "Kabupaten"}, {"kab. ", "Kabupaten"}, {"kab ", "Kabupaten"}, {"kota ", "Kota"},
+	} {
+		if strings.HasPrefix(lower, prefix.prefix) {
+			return prefix.cityType, strings.TrimSpace(name[len(prefix.prefix):])
+		}
+	}
+	return "", name
+}
+
+func mapValues[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
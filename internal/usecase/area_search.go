@@ -0,0 +1,375 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/repository"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Administrative levels of the words of an indexed area, from the broadest to the most specific
+const (
+	areaLevelProvince = iota
+	areaLevelCity
+	areaLevelDistrict
+	areaLevelSubdistrict
+	areaLevelCount
+)
+
+// areaLevelWeights rewards matches on more specific levels, a query naming a district says more than one
+// naming only a province
+var areaLevelWeights = [areaLevelCount]float64{0.25, 0.5, 0.75, 1}
+
+const (
+	// minWordSimilarity is the lowest similarity at which a query word still matches an area word,
+	// allowing about one typo every four letters
+	minWordSimilarity = 0.75
+	// maxSearchCandidates limits how many trigram candidates are scored for a query
+	maxSearchCandidates = 200
+	// matchMargin is how much better the best match must score than the next different area to be trusted
+	matchMargin = 0.05
+)
+
+type AreaSearchConfig struct {
+	MinScore        float64           // Lowest score of a local match used without asking the provider
+	RefreshInterval time.Duration     // How often the index is rebuilt from the database
+	Aliases         map[string]string // Extra abbreviations on top of the built-in dictionary
+}
+
+type AreaSearchResult struct {
+	Area  entity.Area
+	Score float64 // How well the area matches the query, from 0 to 1
+	Level int     // Most specific administrative level matched by the query
+}
+
+type areaSearchEntry struct {
+	area       entity.Area
+	postalCode string
+	words      [areaLevelCount][]string
+}
+
+// AreaSearchIndex is an in-process fuzzy index over the local area table. It finds areas by the names of their
+// province, city, district and subdistrict, tolerating typos and common abbreviations.
+type AreaSearchIndex struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	AreaRepo        *repository.AreaRepository
+	SubdistrictRepo *repository.SubdistrictRepository
+	Config          AreaSearchConfig
+	Settings        *Settings
+
+	mu       sync.RWMutex
+	aliases  map[string]string
+	entries  []areaSearchEntry
+	trigrams map[string][]int
+}
+
+func NewAreaSearchIndex(db *gorm.DB, log *logrus.Logger, areaRepo *repository.AreaRepository,
+	subdistrictRepo *repository.SubdistrictRepository, config AreaSearchConfig, settings *Settings) *AreaSearchIndex {
+	aliases := make(map[string]string, len(defaultAreaAliases)+len(config.Aliases))
+	for abbreviation, full := range defaultAreaAliases {
+		aliases[abbreviation] = full
+	}
+	for abbreviation, full := range config.Aliases {
+		aliases[strings.ToLower(abbreviation)] = normalizeRegionName(full)
+	}
+
+	return &AreaSearchIndex{
+		DB:              db,
+		Log:             log,
+		AreaRepo:        areaRepo,
+		SubdistrictRepo: subdistrictRepo,
+		Config:          config,
+		Settings:        settings,
+		aliases:         aliases,
+		trigrams:        make(map[string][]int),
+	}
+}
+
+// Start builds the index and keeps rebuilding it in the background so mappings changed elsewhere are picked up.
+// The index is built even while searching it is disabled, so enabling it on a config reload takes effect at once.
+func (idx *AreaSearchIndex) Start(ctx context.Context) {
+	go func() {
+		if err := idx.Rebuild(); err != nil {
+			idx.Log.Errorf("Error building area search index: %v", err)
+		}
+		if idx.Config.RefreshInterval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(idx.Config.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := idx.Rebuild(); err != nil {
+					idx.Log.Errorf("Error rebuilding area search index: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Rebuild loads every provider area from the database and replaces the index
+func (idx *AreaSearchIndex) Rebuild() error {
+	areas, err := idx.AreaRepo.FindBySource(idx.DB, "biteship")
+	if err != nil {
+		return err
+	}
+	subdistricts, err := idx.SubdistrictRepo.FindNames(idx.DB)
+	if err != nil {
+		return err
+	}
+	subdistrictNames := make(map[uint]string, len(subdistricts))
+	for _, subdistrict := range subdistricts {
+		subdistrictNames[subdistrict.ID] = subdistrict.Name
+	}
+
+	entries := make([]areaSearchEntry, 0, len(areas))
+	trigrams := make(map[string][]int)
+	for _, area := range areas {
+		entries = appendAreaEntry(entries, trigrams, area, subdistrictNames[area.OriginalSubdistrictID])
+	}
+
+	idx.mu.Lock()
+	idx.entries, idx.trigrams = entries, trigrams
+	idx.mu.Unlock()
+
+	idx.Log.Infof("Area search index built with %d areas", len(entries))
+	return nil
+}
+
+// Add indexes an area right away, used when a new area is saved between two rebuilds
+func (idx *AreaSearchIndex) Add(area entity.Area) {
+	if idx == nil {
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries = appendAreaEntry(idx.entries, idx.trigrams, area, "")
+}
+
+// Search returns up to limit areas matching the query, best first. A five-digit number in the query is taken
+// as a postal code, and postalCode narrows the results to that postal code when given.
+func (idx *AreaSearchIndex) Search(query, postalCode string, limit int) []AreaSearchResult {
+	words := make([]string, 0)
+	for _, word := range expandAreaAliases(strings.Fields(normalizeRegionName(query)), idx.aliases) {
+		if isPostalCode(word) {
+			if postalCode == "" {
+				postalCode = word
+			}
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	results := make([]AreaSearchResult, 0)
+	seen := make(map[string]bool)
+	for _, i := range idx.candidates(words) {
+		entry := &idx.entries[i]
+		if postalCode != "" && entry.postalCode != postalCode {
+			continue
+		}
+
+		score, level := scoreAreaEntry(words, entry)
+		if score == 0 {
+			continue
+		}
+
+		// subdistricts sharing a provider area are the same result
+		if seen[entry.area.ExternalID] {
+			for j := range results {
+				if results[j].Area.ExternalID == entry.area.ExternalID && score > results[j].Score {
+					results[j] = AreaSearchResult{Area: entry.area, Score: score, Level: level}
+				}
+			}
+			continue
+		}
+		seen[entry.area.ExternalID] = true
+		results = append(results, AreaSearchResult{Area: entry.area, Score: score, Level: level})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Level > results[j].Level
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Match returns the local area matching the query when it scores at least the configured minimum and clearly
+// beats any other area, nil when the provider should be asked instead
+func (idx *AreaSearchIndex) Match(query, postalCode string) *AreaSearchResult {
+	if idx == nil || !idx.Settings.Load().AreaSearchEnabled {
+		return nil
+	}
+
+	results := idx.Search(query, postalCode, 2)
+	if len(results) == 0 || results[0].Score < idx.Config.MinScore {
+		return nil
+	}
+	if len(results) > 1 && results[0].Score-results[1].Score < matchMargin {
+		return nil
+	}
+	return &results[0]
+}
+
+// candidates returns the entries sharing the most trigrams with the query words
+func (idx *AreaSearchIndex) candidates(words []string) []int {
+	hits := make(map[int]int)
+	for _, word := range words {
+		for _, trigram := range wordTrigrams(word) {
+			for _, i := range idx.trigrams[trigram] {
+				hits[i]++
+			}
+		}
+	}
+
+	candidates := make([]int, 0, len(hits))
+	for i := range hits {
+		candidates = append(candidates, i)
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if hits[candidates[a]] != hits[candidates[b]] {
+			return hits[candidates[a]] > hits[candidates[b]]
+		}
+		return candidates[a] < candidates[b]
+	})
+	if len(candidates) > maxSearchCandidates {
+		candidates = candidates[:maxSearchCandidates]
+	}
+	return candidates
+}
+
+// scoreAreaEntry rates how well the query words match an area. Most of the score comes from the share of
+// query words found in the area, the rest from how specific the most specific matched level is.
+// Two query words may match a single area word, as in "setia budi" for "Setiabudi".
+func scoreAreaEntry(words []string, entry *areaSearchEntry) (float64, int) {
+	total := 0.0
+	deepest := -1
+	for i := 0; i < len(words); i++ {
+		best, bestLevel := bestWordMatch(words[i], entry)
+		if i+1 < len(words) {
+			if joined, joinedLevel := bestWordMatch(words[i]+words[i+1], entry); joined >= minWordSimilarity && joined > best {
+				best, bestLevel = joined, joinedLevel
+				total += joined
+				i++
+			}
+		}
+		if best < minWordSimilarity {
+			continue
+		}
+		total += best
+		deepest = max(deepest, bestLevel)
+	}
+	if deepest < 0 {
+		return 0, 0
+	}
+
+	coverage := total / float64(len(words))
+	score := 0.85*coverage + 0.15*areaLevelWeights[deepest]
+	return float64(int(score*10000+0.5)) / 10000, deepest
+}
+
+// bestWordMatch returns the similarity of the area word closest to a query word and the level of that area word
+func bestWordMatch(word string, entry *areaSearchEntry) (float64, int) {
+	best, bestLevel := 0.0, -1
+	for level := areaLevelCount - 1; level >= 0; level-- {
+		for _, areaWord := range entry.words[level] {
+			if similarity := wordSimilarity(word, areaWord); similarity > best {
+				best, bestLevel = similarity, level
+			}
+		}
+	}
+	return best, bestLevel
+}
+
+func wordSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+func appendAreaEntry(entries []areaSearchEntry, trigrams map[string][]int, area entity.Area, subdistrictName string) []areaSearchEntry {
+	entry := areaSearchEntry{area: area, postalCode: area.OriginalPostalCode}
+
+	var info biteship.Area
+	if area.ExternalInfo != "" && json.Unmarshal([]byte(area.ExternalInfo), &info) == nil {
+		entry.words[areaLevelProvince] = strings.Fields(normalizeRegionName(info.AdministrativeDivisionLevel1Name))
+		entry.words[areaLevelCity] = strings.Fields(normalizeRegionName(info.AdministrativeDivisionLevel2Name))
+		entry.words[areaLevelDistrict] = strings.Fields(normalizeRegionName(info.AdministrativeDivisionLevel3Name))
+		if entry.postalCode == "" && info.PostalCode > 0 {
+			entry.postalCode = strconv.Itoa(info.PostalCode)
+		}
+	} else {
+		// provider names look like "Kebayoran Baru, Jakarta Selatan, DKI Jakarta. 12120"
+		name := area.Description
+		if i := strings.LastIndex(name, ". "); i >= 0 && isPostalCode(strings.TrimSpace(name[i+2:])) {
+			name = name[:i]
+		}
+		parts := strings.Split(name, ",")
+		for i, part := range parts {
+			level := areaLevelDistrict - i
+			if level < areaLevelProvince {
+				break
+			}
+			entry.words[level] = strings.Fields(normalizeRegionName(part))
+		}
+	}
+	entry.words[areaLevelSubdistrict] = strings.Fields(normalizeRegionName(subdistrictName))
+
+	i := len(entries)
+	indexed := make(map[string]bool)
+	for _, words := range entry.words {
+		for _, word := range words {
+			for _, trigram := range wordTrigrams(word) {
+				if !indexed[trigram] {
+					indexed[trigram] = true
+					trigrams[trigram] = append(trigrams[trigram], i)
+				}
+			}
+		}
+	}
+	return append(entries, entry)
+}
+
+// wordTrigrams returns the three-letter sequences of a word padded with spaces, so short words still have some
+func wordTrigrams(word string) []string {
+	runes := []rune("  " + word + " ")
+	trigrams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+3]))
+	}
+	return trigrams
+}
+
+func isPostalCode(word string) bool {
+	if len(word) != 5 {
+		return false
+	}
+	_, err := strconv.Atoi(word)
+	return err == nil
+}
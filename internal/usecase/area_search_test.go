@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"shipping-gateway/internal/entity"
+	"testing"
+)
+
+func newTestAreaSearchIndex(areas ...entity.Area) *AreaSearchIndex {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	idx := NewAreaSearchIndex(nil, log, nil, nil, AreaSearchConfig{MinScore: 0.85}, NewSettings(RuntimeSettings{AreaSearchEnabled: true}))
+	for _, area := range areas {
+		idx.Add(area)
+	}
+	return idx
+}
+
+func testArea(externalID, description, postalCode string) entity.Area {
+	return entity.Area{ExternalID: externalID, Description: description, OriginalPostalCode: postalCode, ExternalSource: "biteship"}
+}
+
+var testAreas = []entity.Area{
+	testArea("kebayoran-baru", "Kebayoran Baru, Jakarta Selatan, DKI Jakarta. 12120", "12120"),
+	testArea("setiabudi-jakarta", "Setiabudi, Jakarta Selatan, DKI Jakarta. 12910", "12910"),
+	testArea("setiabudi-medan", "Setiabudi, Medan, Sumatera Utara. 20152", "20152"),
+	testArea("coblong", "Coblong, Bandung, Jawa Barat. 40132", "40132"),
+}
+
+func TestWordSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"bandung", "bandung", 1},
+		{"bandng", "bandung", 1 - 1.0/7},
+		{"kebayoran", "kebayroan", 1 - 2.0/9},
+		{"abc", "xyz", 0},
+	}
+	for _, tt := range tests {
+		if got := wordSimilarity(tt.a, tt.b); got-tt.want > 1e-9 || tt.want-got > 1e-9 {
+			t.Errorf("wordSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScoreAreaEntry(t *testing.T) {
+	entries := appendAreaEntry(nil, make(map[string][]int), testAreas[1], "")
+	entry := &entries[0]
+
+	tests := []struct {
+		name      string
+		words     []string
+		wantScore float64
+		wantLevel int
+	}{
+		{"exact district and city", []string{"setiabudi", "jakarta", "selatan"}, 0.85 + 0.15*0.75, areaLevelDistrict},
+		{"split district name", []string{"setia", "budi"}, 0.85 + 0.15*0.75, areaLevelDistrict},
+		{"typo in district", []string{"setiabudy"}, 0.85*(1-1.0/9) + 0.15*0.75, areaLevelDistrict},
+		{"word of city and province counts as city", []string{"jakarta"}, 0.85 + 0.15*0.5, areaLevelCity},
+		{"province only", []string{"dki"}, 0.85 + 0.15*0.25, areaLevelProvince},
+		{"half of the words unknown", []string{"setiabudi", "surabaya"}, 0.425 + 0.15*0.75, areaLevelDistrict},
+		{"no word matches", []string{"surabaya"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, level := scoreAreaEntry(tt.words, entry)
+			if diff := score - tt.wantScore; diff > 0.0001 || diff < -0.0001 || level != tt.wantLevel {
+				t.Errorf("scoreAreaEntry(%v) = %v, %d, want %v, %d", tt.words, score, level, tt.wantScore, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestAreaSearchIndexSearch(t *testing.T) {
+	idx := newTestAreaSearchIndex(testAreas...)
+
+	tests := []struct {
+		name       string
+		query      string
+		postalCode string
+		want       []string
+	}{
+		{"typo", "kebayran baru", "", []string{"kebayoran-baru"}},
+		{"abbreviation", "coblong bdg", "", []string{"coblong"}},
+		{"ambiguous name returns both", "setiabudi", "", []string{"setiabudi-jakarta", "setiabudi-medan"}},
+		{"city breaks the tie", "setiabudi medan", "", []string{"setiabudi-medan", "setiabudi-jakarta"}},
+		{"postal code in query narrows results", "setiabudi 20152", "", []string{"setiabudi-medan"}},
+		{"postal code argument narrows results", "setiabudi", "12910", []string{"setiabudi-jakarta"}},
+		{"postal code only", "12120", "", nil},
+		{"unknown place", "surabaya", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := idx.Search(tt.query, tt.postalCode, 10)
+			got := make([]string, 0, len(results))
+			for _, result := range results {
+				got = append(got, result.Area.ExternalID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q, %q) = %v, want %v", tt.query, tt.postalCode, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Search(%q, %q) = %v, want %v", tt.query, tt.postalCode, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAreaSearchIndexMatch(t *testing.T) {
+	idx := newTestAreaSearchIndex(testAreas...)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"clear match", "kebayoran baru jaksel", "kebayoran-baru"},
+		{"tie within margin", "setiabudi", ""},
+		{"city gives the margin", "setiabudi jakarta selatan", "setiabudi-jakarta"},
+		{"below minimum score", "coblong surabaya", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if match := idx.Match(tt.query, ""); match != nil {
+				got = match.Area.ExternalID
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	idx.Settings.Store(RuntimeSettings{AreaSearchEnabled: false})
+	if match := idx.Match("kebayoran baru", ""); match != nil {
+		t.Errorf("Match with the index disabled = %v, want nil", match.Area.ExternalID)
+	}
+}
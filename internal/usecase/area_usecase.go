@@ -17,20 +17,27 @@ import (
 )
 
 type AreaUseCase struct {
-	BiteshipClient *biteship.Client
-	DB             *gorm.DB
-	Redis          *redis.Client
-	AreaRepo       *repository.AreaRepository
-	Logger         *logrus.Logger
+	BiteshipClient  *biteship.Client
+	DB              *gorm.DB
+	Redis           *redis.Client
+	AreaRepo        *repository.AreaRepository
+	SubdistrictRepo *repository.SubdistrictRepository
+	Logger          *logrus.Logger
+	SearchIndex     *AreaSearchIndex
+	MinMatchScore   float64 // Provider matches scoring below this are flagged for manual review
 }
 
-func NewAreaUseCase(bs *biteship.Client, db *gorm.DB, redis *redis.Client, areaRepo *repository.AreaRepository, logger *logrus.Logger) *AreaUseCase {
+func NewAreaUseCase(bs *biteship.Client, db *gorm.DB, redis *redis.Client, areaRepo *repository.AreaRepository,
+	subdistrictRepo *repository.SubdistrictRepository, logger *logrus.Logger, searchIndex *AreaSearchIndex, minMatchScore float64) *AreaUseCase {
 	return &AreaUseCase{
-		DB:             db,
-		Redis:          redis,
-		AreaRepo:       areaRepo,
-		Logger:         logger,
-		BiteshipClient: bs,
+		DB:              db,
+		Redis:           redis,
+		AreaRepo:        areaRepo,
+		SubdistrictRepo: subdistrictRepo,
+		Logger:          logger,
+		BiteshipClient:  bs,
+		SearchIndex:     searchIndex,
+		MinMatchScore:   minMatchScore,
 	}
 }
 
@@ -57,49 +64,33 @@ func (a *AreaUseCase) FindArea(ctx context.Context, subdistrictID, postalCode, q
 		log.Errorf("Error getting area from Redis: %v", err)
 	}
 
-	// If not found in Redis, query the database
+	// If not found in Redis, use the imported or corrected mapping from the database before searching
 	log.Infof("Cache miss for area with subdistrictID: %s, postalCode: %s", subdistrictID, postalCode)
-	area, err = a.AreaRepo.FindByParameter(a.DB, model.FindAreaParams{
-		OriginalSubdistrictID: subdistrictID,
-		OriginalPostalCode:    postalCode,
-	})
-
-	if err != nil && area != nil && area.ExternalID != "" {
-		// Area found in database, save it to Redis
-		strAreaNew, err := json.Marshal(area)
-		if err != nil {
-			log.Errorf("Error converting area to JSON string: %v", err)
-			goto findFromBiteship
+	if subdistrictID != "" || postalCode != "" {
+		area, err = a.AreaRepo.FindByParameter(a.DB, model.FindAreaParams{
+			OriginalSubdistrictID: subdistrictID,
+			OriginalPostalCode:    postalCode,
+		})
+		if err == nil && area.ExternalID != "" {
+			// Area found in database, save it to Redis under the requested key, which admin changes invalidate
+			if strAreaNew, err := json.Marshal(area); err != nil {
+				log.Errorf("Error converting area to JSON string: %v", err)
+			} else if err = a.Redis.Set(ctx, rdsKey, strAreaNew, 0).Err(); err != nil {
+				log.Errorf("Error setting area in Redis: %v", err)
+			}
+			return area, nil
 		}
-		rdsKey = fmt.Sprintf("area::%d::%s", area.OriginalSubdistrictID, area.OriginalPostalCode)
-		if err = a.Redis.Set(ctx, rdsKey, strAreaNew, 0).Err(); err != nil {
-			log.Errorf("Error setting area in Redis: %v", err)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Errorf("Error finding area in database: %v", err)
 		}
-
-		return area, nil
 	}
 
-findFromBiteship:
 	if query == "" {
 		log.Infof("Skipping area search from Biteship due to empty query")
 		return nil, fmt.Errorf("area query is empty, cannot search area")
 	}
 
-	biteshipArea, errResp := a.BiteshipClient.SearchAreas(query)
-	if errResp != nil {
-		log.Errorf("Error finding area from Biteship: %s", errResp.Error)
-		return nil, fmt.Errorf("failed to find area from Biteship: %s", errResp.Error)
-	}
-
-	if len(biteshipArea.Areas) == 0 {
-		log.Infof("No area found from Biteship for query: %s", query)
-		return nil, fmt.Errorf("no area found for query: %s", query)
-	}
-
-	// Get the first area from Biteship response
-	biteshipFirstArea := biteshipArea.Areas[0]
 	var iSubdistrictID int
-	// Create a new entity.Area from Biteship response
 	if subdistrictID != "" {
 		iSubdistrictID, err = strconv.Atoi(subdistrictID)
 		if err != nil {
@@ -108,20 +99,22 @@ findFromBiteship:
 		}
 	}
 
-	// convert biteshipFirstArea to json string
-	strBiteshipArea, err := json.Marshal(biteshipFirstArea)
-	if err != nil {
-		log.Errorf("Failed to marshal Biteship area: %v", err)
-		return nil, fmt.Errorf("failed to marshal Biteship area: %w", err)
-	}
-
-	area = &entity.Area{
-		OriginalSubdistrictID: uint(iSubdistrictID),
-		OriginalPostalCode:    strconv.Itoa(biteshipFirstArea.PostalCode),
-		Description:           biteshipFirstArea.Name,
-		ExternalSource:        "biteship",
-		ExternalID:            biteshipFirstArea.ID,
-		ExternalInfo:          string(strBiteshipArea),
+	// Try the local index first, searching Biteship is slow and rate limited
+	if match := a.SearchIndex.Match(query, postalCode); match != nil {
+		log.Infof("Area found in local index for query: %s, area: %s, score: %.4f", query, match.Area.ExternalID, match.Score)
+		area = &entity.Area{
+			OriginalSubdistrictID: uint(iSubdistrictID),
+			OriginalPostalCode:    match.Area.OriginalPostalCode,
+			Description:           match.Area.Description,
+			ExternalSource:        match.Area.ExternalSource,
+			ExternalID:            match.Area.ExternalID,
+			ExternalInfo:          match.Area.ExternalInfo,
+			Latitude:              match.Area.Latitude,
+			Longitude:             match.Area.Longitude,
+			MatchScore:            match.Score,
+		}
+	} else if area, err = a.searchBiteship(ctx, query, uint(iSubdistrictID), postalCode); err != nil {
+		return nil, err
 	}
 
 	bArea, _ := json.Marshal(area)
@@ -141,3 +134,62 @@ findFromBiteship:
 
 	return area, nil
 }
+
+// searchBiteship maps a query to the Biteship area that best matches the subdistrict and adds it to the local index.
+// Candidates are scored against the imported subdistrict the way the importer does, or against the postal code
+// alone when the subdistrict was not imported, and weak matches are flagged for review.
+func (a *AreaUseCase) searchBiteship(ctx context.Context, query string, subdistrictID uint, postalCode string) (*entity.Area, error) {
+	log := a.Logger.WithField("traceId", ctx.Value("traceId"))
+
+	biteshipArea, errResp := a.BiteshipClient.SearchAreas(ctx, query)
+	if errResp != nil {
+		log.Errorf("Error finding area from Biteship: %s", errResp.Error)
+		return nil, fmt.Errorf("failed to find area from Biteship: %s", errResp.Error)
+	}
+
+	if len(biteshipArea.Areas) == 0 {
+		log.Infof("No area found from Biteship for query: %s", query)
+		return nil, fmt.Errorf("no area found for query: %s", query)
+	}
+
+	subdistrict := entity.Subdistrict{ID: subdistrictID, PostalCode: postalCode}
+	if subdistrictID > 0 {
+		imported, err := a.SubdistrictRepo.FindByIDWithHierarchy(a.DB, subdistrictID)
+		if err == nil {
+			subdistrict = *imported
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Errorf("Error finding subdistrict %d: %v", subdistrictID, err)
+		}
+	}
+	best, score := bestAreaMatch(biteshipArea.Areas, subdistrict)
+
+	strBiteshipArea, err := json.Marshal(best)
+	if err != nil {
+		log.Errorf("Failed to marshal Biteship area: %v", err)
+		return nil, fmt.Errorf("failed to marshal Biteship area: %w", err)
+	}
+
+	originalPostalCode := subdistrict.PostalCode
+	if originalPostalCode == "" {
+		originalPostalCode = strconv.Itoa(best.PostalCode)
+	}
+
+	area := &entity.Area{
+		OriginalSubdistrictID: subdistrictID,
+		OriginalPostalCode:    originalPostalCode,
+		Description:           best.Name,
+		ExternalSource:        "biteship",
+		ExternalID:            best.ID,
+		ExternalInfo:          string(strBiteshipArea),
+		Latitude:              best.Latitude,
+		Longitude:             best.Longitude,
+		MatchScore:            score,
+		NeedsReview:           score < a.MinMatchScore,
+	}
+	if area.NeedsReview {
+		log.Warnf("Area %s matched query %s with low score %.4f, flagged for review", area.ExternalID, query, score)
+	}
+	a.SearchIndex.Add(*area)
+
+	return area, nil
+}
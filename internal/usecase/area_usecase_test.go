@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/repository"
+	"testing"
+)
+
+func testBiteshipArea(id string, postalCode int, district, city, province string) biteship.Area {
+	return biteship.Area{
+		ID:                               id,
+		Name:                             district + ", " + city + ", " + province,
+		PostalCode:                       postalCode,
+		AdministrativeDivisionLevel1Name: province,
+		AdministrativeDivisionLevel2Name: city,
+		AdministrativeDivisionLevel3Name: district,
+	}
+}
+
+// Biteship search results for "Setiabudi", the first hit is in the wrong city
+var testSetiabudiAreas = []biteship.Area{
+	testBiteshipArea("setiabudi-medan", 20152, "Medan Selayang", "Medan", "Sumatera Utara"),
+	testBiteshipArea("setiabudi-jakarta", 12910, "Setiabudi", "Jakarta Selatan", "DKI Jakarta"),
+}
+
+func testSetiabudiSubdistrict() entity.Subdistrict {
+	return entity.Subdistrict{ID: 3174021001, DistrictID: 3174021, Name: "Karet", PostalCode: "12920",
+		District: entity.District{ID: 3174021, CityID: 3174, Name: "Setiabudi",
+			City: entity.City{ID: 3174, ProvinceID: 31, Type: "Kota", Name: "Jakarta Selatan",
+				Province: entity.Province{ID: 31, Name: "DKI Jakarta"}}}}
+}
+
+func TestBestAreaMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		subdistrict entity.Subdistrict
+		wantID      string
+		wantScore   float64
+	}{
+		{"names outweigh the search order", testSetiabudiSubdistrict(), "setiabudi-jakarta", 0.6},
+		{"postal code alone", entity.Subdistrict{PostalCode: "12910"}, "setiabudi-jakarta", 0.4},
+		{"first hit on equal scores", entity.Subdistrict{PostalCode: "99999"}, "setiabudi-medan", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, score := bestAreaMatch(testSetiabudiAreas, tt.subdistrict)
+			if best == nil || best.ID != tt.wantID || score != tt.wantScore {
+				t.Errorf("bestAreaMatch() = %v, %v, want %s, %v", best, score, tt.wantID, tt.wantScore)
+			}
+		})
+	}
+	if best, _ := bestAreaMatch(nil, testSetiabudiSubdistrict()); best != nil {
+		t.Errorf("bestAreaMatch() without candidates = %v, want nil", best)
+	}
+}
+
+func newTestAreaBiteshipClient(t *testing.T, areas []biteship.Area) *biteship.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(biteship.AreaResponse{Success: true, Areas: areas})
+	}))
+	t.Cleanup(server.Close)
+
+	config := viper.New()
+	config.Set("biteship.base_url", server.URL)
+	config.Set("biteship.retry.max_attempts", 1)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return biteship.NewClient(config, log)
+}
+
+func TestAreaUseCaseSearchBiteship(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	t.Run("scored against the imported subdistrict", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		subdistrict := testSetiabudiSubdistrict()
+		mock.ExpectQuery("FROM `subdistricts`").WithArgs(subdistrict.ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "district_id", "name", "postal_code"}).
+				AddRow(subdistrict.ID, subdistrict.DistrictID, subdistrict.Name, subdistrict.PostalCode))
+		mock.ExpectQuery("FROM `districts`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "city_id", "name"}).AddRow(3174021, 3174, "Setiabudi"))
+		mock.ExpectQuery("FROM `cities`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "province_id", "type", "name"}).AddRow(3174, 31, "Kota", "Jakarta Selatan"))
+		mock.ExpectQuery("FROM `provinces`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(31, "DKI Jakarta"))
+
+		uc := &AreaUseCase{BiteshipClient: newTestAreaBiteshipClient(t, testSetiabudiAreas), DB: db, Logger: log,
+			SubdistrictRepo: repository.NewSubdistrictRepository(), MinMatchScore: 0.5}
+		area, err := uc.searchBiteship(context.Background(), "Setiabudi", uint(subdistrict.ID), "12920")
+		if err != nil {
+			t.Fatalf("searchBiteship() error = %v", err)
+		}
+		if area.ExternalID != "setiabudi-jakarta" || area.MatchScore != 0.6 || area.NeedsReview || area.OriginalPostalCode != "12920" {
+			t.Errorf("searchBiteship() = %+v, want the Jakarta area as a confident match", area)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("weak match flagged for review", func(t *testing.T) {
+		uc := &AreaUseCase{BiteshipClient: newTestAreaBiteshipClient(t, testSetiabudiAreas), Logger: log, MinMatchScore: 0.5}
+		area, err := uc.searchBiteship(context.Background(), "Setiabudi", 0, "12910")
+		if err != nil {
+			t.Fatalf("searchBiteship() error = %v", err)
+		}
+		if area.ExternalID != "setiabudi-jakarta" || area.MatchScore != 0.4 || !area.NeedsReview {
+			t.Errorf("searchBiteship() = %+v, want the postal code match flagged for review", area)
+		}
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		uc := &AreaUseCase{BiteshipClient: newTestAreaBiteshipClient(t, nil), Logger: log}
+		if _, err := uc.searchBiteship(context.Background(), "Nowhere", 0, ""); err == nil {
+			t.Error("searchBiteship() error = nil, want no area found")
+		}
+	})
+}
+
+func TestAreaUseCaseFindAreaKeepsOverrideOnCacheMiss(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	rds := miniredis.RunT(t)
+	db, mock := newMockDB(t)
+
+	// the admin override: corrected external ID, full confidence and no review pending
+	mock.ExpectQuery("FROM `areas` WHERE original_subdistrict_id = \\? AND original_postal_code = \\?").
+		WithArgs("3174021001", "12920", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "original_subdistrict_id", "original_postal_code", "external_source",
+			"external_id", "match_score", "needs_review"}).
+			AddRow(7, 3174021001, "12920", "biteship", "override-area", 1, false))
+
+	// Biteship must not be asked, and the area must not be saved again
+	uc := &AreaUseCase{BiteshipClient: newTestAreaBiteshipClient(t, testSetiabudiAreas), DB: db, Logger: log,
+		Redis: redis.NewClient(&redis.Options{Addr: rds.Addr()}), AreaRepo: repository.NewAreaRepository(), MinMatchScore: 0.5}
+	area, err := uc.FindArea(context.Background(), "3174021001", "12920", "Karet, Setiabudi")
+	if err != nil {
+		t.Fatalf("FindArea() error = %v", err)
+	}
+	if area.ExternalID != "override-area" || area.MatchScore != 1 || area.NeedsReview {
+		t.Errorf("FindArea() = %+v, want the override", area)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	cached, err := rds.Get("area::3174021001::12920")
+	if err != nil {
+		t.Fatalf("cached area: %v", err)
+	}
+	var cachedArea entity.Area
+	if err := json.Unmarshal([]byte(cached), &cachedArea); err != nil || cachedArea.ExternalID != "override-area" {
+		t.Errorf("cached area = %s, want the override", cached)
+	}
+
+	// served from the cache from now on
+	if area, err := uc.FindArea(context.Background(), "3174021001", "12920", "Karet, Setiabudi"); err != nil || area.ExternalID != "override-area" {
+		t.Errorf("FindArea() from cache = %+v, %v, want the override", area, err)
+	}
+}
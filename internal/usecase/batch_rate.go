@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"sort"
+	"sync"
+)
+
+// areaFinder resolves a location to an area, see AreaUseCase.FindArea
+type areaFinder func(ctx context.Context, subdistrictID, postalCode, query string) (*entity.Area, error)
+
+type areaCall struct {
+	once sync.Once
+	area *entity.Area
+	err  error
+}
+
+// memoizedFindArea wraps AreaUseCase.FindArea so each distinct location is only resolved once
+func (uc *ShippingUseCase) memoizedFindArea() areaFinder {
+	var mu sync.Mutex
+	calls := make(map[string]*areaCall)
+
+	return func(ctx context.Context, subdistrictID, postalCode, query string) (*entity.Area, error) {
+		key := subdistrictID + "::" + postalCode + "::" + query
+
+		mu.Lock()
+		call, ok := calls[key]
+		if !ok {
+			call = &areaCall{}
+			calls[key] = call
+		}
+		mu.Unlock()
+
+		call.once.Do(func() {
+			call.area, call.err = uc.AreaUseCase.FindArea(ctx, subdistrictID, postalCode, query)
+		})
+		return call.area, call.err
+	}
+}
+
+// GetBatchCourierRates quotes every leg concurrently and combines the services that are available on all legs
+func (uc *ShippingUseCase) GetBatchCourierRates(ctx context.Context, req *model.BatchRateRequest) (*model.ServiceResponse, *model.BatchRateResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+	log.Infof("GetBatchCourierRates request with %d legs", len(req.Legs))
+
+	findArea := uc.memoizedFindArea()
+	legs := make([]model.BatchRateLeg, len(req.Legs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(uc.Config.BatchConcurrency, 1))
+	for i := range req.Legs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			legReq := req.Legs[i]
+			ucResp, resp := uc.getCourierRates(ctx, &legReq, findArea)
+			legs[i] = model.BatchRateLeg{Index: i, Code: ucResp.StatusCode}
+			if ucResp.StatusCode != http.StatusOK {
+				legs[i].Status = "failed"
+				legs[i].Message = ucResp.Message
+				return
+			}
+			legs[i].Status = "success"
+			legs[i].Data = &resp.Data
+		}(i)
+	}
+	wg.Wait()
+
+	var resp model.BatchRateResponse
+	resp.Response.Status = "success"
+	resp.Response.Code = http.StatusOK
+	resp.Response.Message = "success"
+	resp.Data.Legs = legs
+	resp.Data.Combined = combineLegPrices(legs)
+	return model.Success(), &resp
+}
+
+// combineLegPrices sums the prices of every courier service quoted on all legs. Nothing is combined
+// when a leg failed, since no single courier can then ship the whole order.
+func combineLegPrices(legs []model.BatchRateLeg) []model.CombinedCourierPrice {
+	combined := make([]model.CombinedCourierPrice, 0)
+	for _, leg := range legs {
+		if leg.Data == nil {
+			return combined
+		}
+	}
+
+	byService := make(map[string]*model.CombinedCourierPrice)
+	counts := make(map[string]int)
+	order := make([]string, 0)
+	for _, leg := range legs {
+		seen := make(map[string]bool)
+		for _, price := range leg.Data.Prices {
+			key := price.CourierCode + "::" + price.ServiceCode
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			total, ok := byService[key]
+			if !ok {
+				total = &model.CombinedCourierPrice{
+					CourierCode: price.CourierCode,
+					CourierName: price.CourierName,
+					ServiceCode: price.ServiceCode,
+					ServiceName: price.ServiceName,
+					QuoteIDs:    make([]string, 0, len(legs)),
+				}
+				byService[key] = total
+				order = append(order, key)
+			}
+			total.Price += price.Price
+			total.Total += price.Breakdown.Total
+			total.QuoteIDs = append(total.QuoteIDs, price.QuoteID)
+			counts[key]++
+		}
+	}
+
+	for _, key := range order {
+		if counts[key] == len(legs) {
+			combined = append(combined, *byService[key])
+		}
+	}
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Total < combined[j].Total
+	})
+	return combined
+}
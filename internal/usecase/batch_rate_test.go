@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"reflect"
+	"shipping-gateway/internal/model"
+	"testing"
+)
+
+func testLeg(prices ...model.CourierPrice) model.BatchRateLeg {
+	return model.BatchRateLeg{Status: "success", Data: &model.CourierRate{Prices: prices}}
+}
+
+func testLegPrice(courierCode, serviceCode string, price, total int, quoteID string) model.CourierPrice {
+	return model.CourierPrice{
+		CourierCode: courierCode,
+		ServiceCode: serviceCode,
+		Price:       price,
+		Breakdown:   model.PriceBreakdown{Total: total},
+		QuoteID:     quoteID,
+	}
+}
+
+func TestCombineLegPrices(t *testing.T) {
+	tests := []struct {
+		name string
+		legs []model.BatchRateLeg
+		want []model.CombinedCourierPrice
+	}{
+		{
+			name: "services on every leg are summed and sorted by total",
+			legs: []model.BatchRateLeg{
+				testLeg(testLegPrice("jne", "reg", 9000, 10000, "q1"), testLegPrice("sicepat", "reg", 7000, 8000, "q2")),
+				testLeg(testLegPrice("sicepat", "reg", 6000, 6500, "q3"), testLegPrice("jne", "reg", 8000, 8500, "q4")),
+			},
+			want: []model.CombinedCourierPrice{
+				{CourierCode: "sicepat", ServiceCode: "reg", Price: 13000, Total: 14500, QuoteIDs: []string{"q2", "q3"}},
+				{CourierCode: "jne", ServiceCode: "reg", Price: 17000, Total: 18500, QuoteIDs: []string{"q1", "q4"}},
+			},
+		},
+		{
+			name: "services missing on a leg are dropped",
+			legs: []model.BatchRateLeg{
+				testLeg(testLegPrice("jne", "reg", 9000, 9000, "q1"), testLegPrice("jne", "yes", 15000, 15000, "q2")),
+				testLeg(testLegPrice("jne", "reg", 8000, 8000, "q3")),
+			},
+			want: []model.CombinedCourierPrice{
+				{CourierCode: "jne", ServiceCode: "reg", Price: 17000, Total: 17000, QuoteIDs: []string{"q1", "q3"}},
+			},
+		},
+		{
+			name: "duplicate service on a leg counted once",
+			legs: []model.BatchRateLeg{
+				testLeg(testLegPrice("jne", "reg", 9000, 9000, "q1"), testLegPrice("jne", "reg", 9500, 9500, "q2")),
+				testLeg(testLegPrice("jne", "yes", 8000, 8000, "q3")),
+			},
+			want: []model.CombinedCourierPrice{},
+		},
+		{
+			name: "nothing combined when a leg failed",
+			legs: []model.BatchRateLeg{
+				testLeg(testLegPrice("jne", "reg", 9000, 9000, "q1")),
+				{Status: "failed", Message: "Area not found"},
+			},
+			want: []model.CombinedCourierPrice{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combineLegPrices(tt.legs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("combineLegPrices() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
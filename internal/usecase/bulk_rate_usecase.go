@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/model/converter"
+	"shipping-gateway/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bulkRateColumns are the columns every uploaded CSV must have, in any order
+var bulkRateColumns = []string{"origin_postal_code", "destination_postal_code", "weight", "length", "width", "height", "couriers"}
+
+var bulkRateResultHeader = []string{"line", "origin_postal_code", "destination_postal_code", "weight", "length", "width", "height",
+	"courier_code", "courier_name", "service_code", "service_name", "service_type", "price", "chargeable_weight", "etd", "error"}
+
+type BulkRateConfig struct {
+	StorageDir    string        // Directory for uploaded and result files
+	MaxRows       int           // Maximum number of routes in one upload
+	Workers       int           // Number of jobs processed concurrently
+	RowInterval   time.Duration // Minimum delay between two priced routes across all workers
+	ProgressEvery int           // Persist progress after this many routes
+	QueueSize     int           // Number of jobs that can wait in the queue
+}
+
+// courierRater quotes a single route, see ShippingUseCase.GetCourierRates
+type courierRater func(ctx context.Context, req *model.CourierRateRequest) (*model.ServiceResponse, *model.CourierRateResponse)
+
+type BulkRateUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	ShippingUseCase *ShippingUseCase
+	JobRepo         *repository.BulkRateJobRepository
+	Config          BulkRateConfig
+
+	queue    chan string
+	limiter  *time.Ticker
+	getRates courierRater
+}
+
+func NewBulkRateUseCase(db *gorm.DB, log *logrus.Logger, shippingUseCase *ShippingUseCase,
+	jobRepo *repository.BulkRateJobRepository, config BulkRateConfig) *BulkRateUseCase {
+	return &BulkRateUseCase{
+		DB:              db,
+		Log:             log,
+		ShippingUseCase: shippingUseCase,
+		JobRepo:         jobRepo,
+		Config:          config,
+		queue:           make(chan string, max(config.QueueSize, 1)),
+		limiter:         time.NewTicker(max(config.RowInterval, time.Millisecond)),
+		getRates:        shippingUseCase.GetCourierRates,
+	}
+}
+
+// Start launches the background workers and queues jobs left unfinished by a previous run
+func (uc *BulkRateUseCase) Start(ctx context.Context) {
+	for i := 0; i < max(uc.Config.Workers, 1); i++ {
+		go uc.work(ctx)
+	}
+
+	jobs, err := uc.JobRepo.FindUnfinished(uc.DB)
+	if err != nil {
+		uc.Log.Errorf("Error loading unfinished bulk rate jobs: %v", err)
+		return
+	}
+	go func() {
+		for _, job := range jobs {
+			uc.Log.Infof("Resuming bulk rate job %s", job.ID)
+			uc.queue <- job.ID
+		}
+	}()
+}
+
+// CreateJob validates and stores an uploaded route list, then queues it for pricing
+func (uc *BulkRateUseCase) CreateJob(ctx context.Context, fileName string, file io.Reader) (*model.ServiceResponse, *model.BulkRateJobResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		log.Errorf("Error reading uploaded file: %v", err)
+		return model.BadRequest("Failed to read uploaded file", nil), nil
+	}
+
+	rows, err := parseBulkRateRows(bytes.NewReader(content))
+	if err != nil {
+		return model.BadRequest(err.Error(), nil), nil
+	}
+	if len(rows) == 0 {
+		return model.BadRequest("Uploaded file contains no routes", nil), nil
+	}
+	if len(rows) > uc.Config.MaxRows {
+		return model.BadRequest(fmt.Sprintf("Uploaded file must not contain more than %d routes", uc.Config.MaxRows), nil), nil
+	}
+
+	if err := os.MkdirAll(uc.Config.StorageDir, 0o755); err != nil {
+		log.Errorf("Error creating bulk rate storage dir: %v", err)
+		return model.DefaultError("Failed to store uploaded file", nil), nil
+	}
+
+	id, _ := uuid.NewV7()
+	job := &entity.BulkRateJob{
+		ID:         id.String(),
+		MerchantID: TenantID(ctx),
+		FileName:   filepath.Base(fileName),
+		Status:     entity.BulkRateJobPending,
+		TotalRows:  len(rows),
+		InputPath:  filepath.Join(uc.Config.StorageDir, id.String()+".csv"),
+	}
+
+	if err := os.WriteFile(job.InputPath, content, 0o644); err != nil {
+		log.Errorf("Error storing uploaded file: %v", err)
+		return model.DefaultError("Failed to store uploaded file", nil), nil
+	}
+
+	if err := uc.JobRepo.Create(uc.DB, job); err != nil {
+		log.Errorf("Error creating bulk rate job: %v", err)
+		return model.DefaultError("Failed to create bulk rate job", nil), nil
+	}
+
+	select {
+	case uc.queue <- job.ID:
+	default:
+		// a pending job would only be picked up on the next start, so the client is told to upload it again later
+		log.Warnf("Bulk rate queue is full, rejecting job %s", job.ID)
+		finished := time.Now()
+		job.Status = entity.BulkRateJobFailed
+		job.Error = "bulk rate queue is full"
+		job.FinishedAt = &finished
+		if err := uc.JobRepo.Update(uc.DB, job); err != nil {
+			log.Errorf("Error updating bulk rate job: %v", err)
+		}
+		if err := os.Remove(job.InputPath); err != nil {
+			log.Errorf("Error removing uploaded file: %v", err)
+		}
+		return model.ServiceUnavailable("Bulk rate queue is full, please retry later"), nil
+	}
+
+	return &model.ServiceResponse{StatusCode: http.StatusAccepted, Message: "Accepted"}, converter.BulkRateJobToResponse(job)
+}
+
+func (uc *BulkRateUseCase) GetJob(ctx context.Context, id string) (*model.ServiceResponse, *model.BulkRateJobResponse) {
+	ucResp, job := uc.findJob(ctx, id)
+	if job == nil {
+		return ucResp, nil
+	}
+
+	return model.Success(), converter.BulkRateJobToResponse(job)
+}
+
+// GetResult returns the content of a completed job result as CSV or XLSX
+func (uc *BulkRateUseCase) GetResult(ctx context.Context, id, format string) (*model.ServiceResponse, []byte) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, job := uc.findJob(ctx, id)
+	if job == nil {
+		return ucResp, nil
+	}
+
+	if job.Status != entity.BulkRateJobCompleted {
+		return &model.ServiceResponse{StatusCode: http.StatusConflict, Message: "Bulk rate job has not completed yet"}, nil
+	}
+
+	content, err := os.ReadFile(job.ResultPath)
+	if err != nil {
+		log.Errorf("Error reading bulk rate result %s: %v", job.ResultPath, err)
+		return model.DefaultError("Failed to read bulk rate result", nil), nil
+	}
+
+	switch format {
+	case "", "csv":
+		return model.Success(), content
+	case "xlsx":
+		xlsx, err := csvToXLSX(content)
+		if err != nil {
+			log.Errorf("Error converting bulk rate result to xlsx: %v", err)
+			return model.DefaultError("Failed to convert bulk rate result", nil), nil
+		}
+		return model.Success(), xlsx
+	default:
+		return model.BadRequest("Format must be csv or xlsx", nil), nil
+	}
+}
+
+func (uc *BulkRateUseCase) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-uc.queue:
+			uc.process(ctx, id)
+		}
+	}
+}
+
+// findJob loads a job of the tenant of the request, jobs of other tenants are reported missing
+func (uc *BulkRateUseCase) findJob(ctx context.Context, id string) (*model.ServiceResponse, *entity.BulkRateJob) {
+	job, err := uc.JobRepo.FindByID(uc.DB, id)
+	if err == nil && job.MerchantID != TenantID(ctx) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.NotFound("Bulk rate job not found"), nil
+		}
+		uc.Log.WithField("traceId", ctx.Value("traceId")).Errorf("Error finding bulk rate job %s: %v", id, err)
+		return model.DefaultError("Failed to get bulk rate job", nil), nil
+	}
+	return model.Success(), job
+}
+
+// process prices every route of a job, writing one result line per quoted service
+func (uc *BulkRateUseCase) process(ctx context.Context, id string) {
+	ctx = context.WithValue(ctx, "traceId", "bulk-"+id)
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	job, err := uc.JobRepo.FindByID(uc.DB, id)
+	if err != nil {
+		log.Errorf("Error loading bulk rate job: %v", err)
+		return
+	}
+
+	now := time.Now()
+	job.Status = entity.BulkRateJobProcessing
+	job.StartedAt = &now
+	job.ProcessedRows = 0
+	job.FailedRows = 0
+	job.ResultPath = filepath.Join(uc.Config.StorageDir, job.ID+"_result.csv")
+	if err := uc.JobRepo.Update(uc.DB, job); err != nil {
+		log.Errorf("Error updating bulk rate job: %v", err)
+		return
+	}
+
+	// routes are priced with the provider account and pricing rules of the merchant that uploaded them
+	if err := uc.priceRows(WithTenant(ctx, job.MerchantID), job); err != nil {
+		log.Errorf("Bulk rate job failed: %v", err)
+		job.Status = entity.BulkRateJobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = entity.BulkRateJobCompleted
+	}
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err := uc.JobRepo.Update(uc.DB, job); err != nil {
+		log.Errorf("Error updating bulk rate job: %v", err)
+	}
+	log.Infof("Bulk rate job finished with status %s, %d/%d routes failed", job.Status, job.FailedRows, job.TotalRows)
+}
+
+func (uc *BulkRateUseCase) priceRows(ctx context.Context, job *entity.BulkRateJob) error {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	input, err := os.Open(job.InputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer input.Close()
+
+	rows, err := parseBulkRateRows(input)
+	if err != nil {
+		return err
+	}
+
+	output, err := os.Create(job.ResultPath)
+	if err != nil {
+		return fmt.Errorf("failed to create result file: %w", err)
+	}
+	defer output.Close()
+
+	writer := csv.NewWriter(output)
+	if err := writer.Write(bulkRateResultHeader); err != nil {
+		return fmt.Errorf("failed to write result file: %w", err)
+	}
+
+	for _, row := range rows {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-uc.limiter.C:
+		}
+
+		records, ok := uc.priceRow(ctx, row)
+		for _, record := range records {
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write result file: %w", err)
+			}
+		}
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to write result file: %w", err)
+		}
+
+		job.ProcessedRows++
+		if !ok {
+			job.FailedRows++
+		}
+		if job.ProcessedRows%max(uc.Config.ProgressEvery, 1) == 0 {
+			writer.Flush()
+			if err := uc.JobRepo.UpdateProgress(uc.DB, job); err != nil {
+				log.Errorf("Error updating bulk rate job progress: %v", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// priceRow quotes a single route and returns its result lines, reporting whether the route could be priced
+func (uc *BulkRateUseCase) priceRow(ctx context.Context, row model.BulkRateRow) ([][]string, bool) {
+	prefix := []string{strconv.Itoa(row.Line), row.OriginPostalCode, row.DestinationPostalCode,
+		strconv.Itoa(row.Weight), strconv.Itoa(row.Length), strconv.Itoa(row.Width), strconv.Itoa(row.Height)}
+
+	req := &model.CourierRateRequest{
+		OriginPostalCode:      row.OriginPostalCode,
+		OriginQuery:           row.OriginPostalCode,
+		DestinationPostalCode: row.DestinationPostalCode,
+		DestinationQuery:      row.DestinationPostalCode,
+		CourierCode:           row.CourierCode,
+		Items:                 []model.ItemRequest{{Name: "item", Metrics: row.Metrics, Quantity: 1}},
+		SkipQuote:             true,
+	}
+
+	ucResp, resp := uc.getRates(ctx, req)
+	if ucResp.StatusCode != http.StatusOK {
+		return [][]string{append(prefix, "", "", "", "", "", "", "", "", ucResp.Message)}, false
+	}
+
+	records := make([][]string, 0, len(resp.Data.Prices))
+	for _, price := range resp.Data.Prices {
+		record := append(append([]string(nil), prefix...),
+			price.CourierCode, price.CourierName, price.ServiceCode, price.ServiceName, price.ServiceType,
+			strconv.Itoa(price.Price), strconv.Itoa(price.Weight.Chargeable), price.ETD, "")
+		records = append(records, record)
+	}
+	return records, true
+}
+
+// parseBulkRateRows reads the routes of an uploaded CSV. Couriers may be separated by commas,
+// semicolons or pipes within their cell.
+func parseBulkRateRows(r io.Reader) ([]model.BulkRateRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range bulkRateColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header must contain column '%s'", name)
+		}
+	}
+
+	rows := make([]model.BulkRateRow, 0)
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := model.BulkRateRow{
+			Line:                  line,
+			OriginPostalCode:      strings.TrimSpace(record[columns["origin_postal_code"]]),
+			DestinationPostalCode: strings.TrimSpace(record[columns["destination_postal_code"]]),
+			CourierCode: strings.NewReplacer(";", ",", "|", ",", " ", "").
+				Replace(record[columns["couriers"]]),
+		}
+		if row.OriginPostalCode == "" || row.DestinationPostalCode == "" || row.CourierCode == "" {
+			return nil, fmt.Errorf("line %d: postal codes and couriers must be provided", line)
+		}
+
+		metrics := []struct {
+			column string
+			value  *int
+		}{
+			{"weight", &row.Weight}, {"length", &row.Length}, {"width", &row.Width}, {"height", &row.Height},
+		}
+		for _, metric := range metrics {
+			value, err := strconv.Atoi(strings.TrimSpace(record[columns[metric.column]]))
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("line %d: column '%s' must be a non-negative integer", line, metric.column)
+			}
+			*metric.value = value
+		}
+		if row.Weight == 0 {
+			return nil, fmt.Errorf("line %d: column 'weight' must be greater than zero", line)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvToXLSX converts a CSV document to a single sheet workbook, keeping numeric cells numeric
+func csvToXLSX(content []byte) ([]byte, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := file.GetSheetName(0)
+	for i, record := range records {
+		values := make([]interface{}, len(record))
+		for j, value := range record {
+			if number, err := strconv.Atoi(value); err == nil && i > 0 {
+				values[j] = number
+				continue
+			}
+			values[j] = value
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := file.SetSheetRow(sheet, cell, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
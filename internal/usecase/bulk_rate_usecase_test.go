@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"os"
+	"path/filepath"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	return db, mock
+}
+
+func TestParseBulkRateRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []model.BulkRateRow
+		wantErr string
+	}{
+		{
+			name: "columns in any order with courier separators",
+			csv: "\ufeffCouriers,weight,origin_postal_code,destination_postal_code,length,width,height\n" +
+				"\"jne; sicepat|pos\",1000, 12440 ,40115,10,20,30\n",
+			want: []model.BulkRateRow{{
+				Line: 2, OriginPostalCode: "12440", DestinationPostalCode: "40115", CourierCode: "jne,sicepat,pos",
+				Metrics: model.Metrics{Weight: 1000, Length: 10, Width: 20, Height: 30},
+			}},
+		},
+		{
+			name: "line numbers follow the file",
+			csv: "origin_postal_code,destination_postal_code,weight,length,width,height,couriers\n" +
+				"12440,40115,1000,0,0,0,jne\n12440,60111,500,0,0,0,tiki\n",
+			want: []model.BulkRateRow{
+				{Line: 2, OriginPostalCode: "12440", DestinationPostalCode: "40115", CourierCode: "jne", Metrics: model.Metrics{Weight: 1000}},
+				{Line: 3, OriginPostalCode: "12440", DestinationPostalCode: "60111", CourierCode: "tiki", Metrics: model.Metrics{Weight: 500}},
+			},
+		},
+		{
+			name:    "missing column",
+			csv:     "origin_postal_code,destination_postal_code,weight,length,width,height\n",
+			wantErr: "CSV header must contain column 'couriers'",
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "failed to read CSV header",
+		},
+		{
+			name: "missing postal code",
+			csv: "origin_postal_code,destination_postal_code,weight,length,width,height,couriers\n" +
+				"12440,,1000,0,0,0,jne\n",
+			wantErr: "line 2: postal codes and couriers must be provided",
+		},
+		{
+			name: "negative metric",
+			csv: "origin_postal_code,destination_postal_code,weight,length,width,height,couriers\n" +
+				"12440,40115,1000,-1,0,0,jne\n",
+			wantErr: "line 2: column 'length' must be a non-negative integer",
+		},
+		{
+			name: "zero weight",
+			csv: "origin_postal_code,destination_postal_code,weight,length,width,height,couriers\n" +
+				"12440,40115,1000,0,0,0,jne\n12440,40115,0,0,0,0,jne\n",
+			wantErr: "line 3: column 'weight' must be greater than zero",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseBulkRateRows(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseBulkRateRows() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBulkRateRows() error = %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("parseBulkRateRows() = %+v, want %+v", rows, tt.want)
+			}
+			for i := range rows {
+				if rows[i] != tt.want[i] {
+					t.Errorf("row %d = %+v, want %+v", i, rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBulkRatePriceRowsCountsFailedRows(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "job.csv")
+	content := "origin_postal_code,destination_postal_code,weight,length,width,height,couriers\n" +
+		"12440,40115,1000,0,0,0,jne\n12440,99999,1000,0,0,0,jne\n"
+	if err := os.WriteFile(input, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, mock := newMockDB(t)
+	for _, counts := range [][2]int{{1, 0}, {2, 1}} {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `bulk_rate_jobs` SET `processed_rows`=\\?,`failed_rows`=\\?,`updated_at`=\\? WHERE `id` = \\?").
+			WithArgs(counts[0], counts[1], sqlmock.AnyArg(), "job-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	uc := &BulkRateUseCase{
+		DB:      db,
+		Log:     log,
+		JobRepo: repository.NewBulkRateJobRepository(),
+		Config:  BulkRateConfig{ProgressEvery: 1},
+		limiter: time.NewTicker(time.Millisecond),
+		getRates: func(ctx context.Context, req *model.CourierRateRequest) (*model.ServiceResponse, *model.CourierRateResponse) {
+			if req.DestinationPostalCode == "99999" {
+				return model.NotFound("Destination area not found"), nil
+			}
+			var resp model.CourierRateResponse
+			resp.Data.Prices = []model.CourierPrice{{CourierCode: "jne", ServiceCode: "reg", Price: 9000}}
+			return model.Success(), &resp
+		},
+	}
+	defer uc.limiter.Stop()
+
+	job := &entity.BulkRateJob{ID: "job-1", InputPath: input, ResultPath: filepath.Join(dir, "job_result.csv")}
+	if err := uc.priceRows(context.Background(), job); err != nil {
+		t.Fatalf("priceRows() error = %v", err)
+	}
+
+	if job.ProcessedRows != 2 || job.FailedRows != 1 {
+		t.Errorf("ProcessedRows = %d, FailedRows = %d, want 2 and 1", job.ProcessedRows, job.FailedRows)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	result, err := os.Open(job.ResultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer result.Close()
+	records, err := csv.NewReader(result).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1][7] != "jne" || records[2][len(records[2])-1] != "Destination area not found" {
+		t.Errorf("result = %v, want a priced line and a failed line", records)
+	}
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import "shipping-gateway/internal/entity"
+
+// defaultCouriers returns the couriers seeded into the couriers table with the aliases clients and Biteship use
+// for them. The first Biteship alias of each courier is the code sent to Biteship.
+func defaultCouriers() []entity.Courier {
+	courier := func(code, name string, biteship []string, client ...string) entity.Courier {
+		aliases := make([]entity.CourierAlias, 0, len(biteship)+len(client))
+		for i, alias := range biteship {
+			aliases = append(aliases, entity.CourierAlias{Provider: ProviderBiteship, Alias: alias, IsPrimary: i == 0})
+		}
+		for _, alias := range client {
+			aliases = append(aliases, entity.CourierAlias{Alias: alias})
+		}
+		return entity.Courier{Code: code, Name: name, Aliases: aliases}
+	}
+
+	return []entity.Courier{
+		courier("jne", "JNE", []string{"jne"}),
+		courier("jnt", "J&T Express", []string{"jnt", "j&t"}, "j&t", "j&t express", "jt"),
+		courier("sicepat", "SiCepat", []string{"sicepat"}, "si cepat"),
+		courier("anteraja", "AnterAja", []string{"anteraja"}, "anter aja"),
+		courier("ninja", "Ninja Xpress", []string{"ninja"}, "ninjaxpress", "ninja van"),
+		courier("idexpress", "ID Express", []string{"idexpress"}, "ide", "id express"),
+		courier("pos", "Pos Indonesia", []string{"pos"}, "posindonesia", "pos indonesia"),
+		courier("tiki", "TIKI", []string{"tiki"}),
+		courier("lion", "Lion Parcel", []string{"lion"}, "lionparcel", "lion parcel"),
+		courier("sap", "SAP Express", []string{"sap"}),
+		courier("gojek", "GoSend", []string{"gojek"}, "gosend"),
+		courier("grab", "GrabExpress", []string{"grab"}, "grabexpress"),
+	}
+}
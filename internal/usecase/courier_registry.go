@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/repository"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderBiteship is the provider name of Biteship courier aliases
+const ProviderBiteship = "biteship"
+
+// CourierRegistry maps the many spellings of a courier to its canonical code from the couriers table.
+// Client requests, provider responses, cache keys and stored records all use the canonical code, while each
+// provider is called with its own code. Unknown codes pass through lowercased.
+type CourierRegistry struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	CourierRepo     *repository.CourierRepository
+	TrackingLogRepo *repository.TrackingLogRepository
+	RefreshInterval time.Duration
+
+	mu            sync.RWMutex
+	canonical     map[string]map[string]string // provider -> alias -> canonical code
+	providerCodes map[string]map[string]string // provider -> canonical code -> provider code
+}
+
+func NewCourierRegistry(db *gorm.DB, log *logrus.Logger, courierRepo *repository.CourierRepository,
+	trackingLogRepo *repository.TrackingLogRepository, refreshInterval time.Duration) *CourierRegistry {
+	return &CourierRegistry{
+		DB:              db,
+		Log:             log,
+		CourierRepo:     courierRepo,
+		TrackingLogRepo: trackingLogRepo,
+		RefreshInterval: refreshInterval,
+		canonical:       make(map[string]map[string]string),
+		providerCodes:   make(map[string]map[string]string),
+	}
+}
+
+// Start seeds the default couriers, loads the registry and keeps reloading it in the background so couriers
+// added to the table are picked up
+func (r *CourierRegistry) Start(ctx context.Context) {
+	if err := r.Seed(); err != nil {
+		r.Log.Errorf("Error seeding default couriers: %v", err)
+	}
+	if err := r.Reload(); err != nil {
+		r.Log.Errorf("Error loading courier registry: %v", err)
+	}
+	if r.RefreshInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Reload(); err != nil {
+					r.Log.Errorf("Error reloading courier registry: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Reload replaces the registry with the couriers and aliases in the database
+func (r *CourierRegistry) Reload() error {
+	couriers, err := r.CourierRepo.FindAllWithAliases(r.DB)
+	if err != nil {
+		return err
+	}
+
+	r.load(couriers)
+	r.Log.Infof("Courier registry loaded with %d couriers", len(couriers))
+	return nil
+}
+
+// Seed inserts the default couriers missing from the table, so a new database resolves the common spellings
+// of each courier. Couriers already in the table, or deleted from it, are left as they are.
+func (r *CourierRegistry) Seed() error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		created := 0
+		for _, courier := range defaultCouriers() {
+			inserted, err := r.CourierRepo.CreateIfMissing(tx, &courier)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				created++
+			}
+		}
+		if created == 0 {
+			return nil
+		}
+
+		// tracking logs stored before the courier existed may hold a provider spelling of its code
+		updated, err := r.TrackingLogRepo.CanonicalizeCourierCodes(tx)
+		if err != nil {
+			return err
+		}
+		r.Log.Infof("Seeded %d default couriers, canonicalized the courier code of %d tracking logs", created, updated)
+		return nil
+	})
+}
+
+// load replaces the registry with the given couriers and their aliases
+func (r *CourierRegistry) load(couriers []entity.Courier) {
+	canonical := make(map[string]map[string]string)
+	providerCodes := make(map[string]map[string]string)
+	add := func(m map[string]map[string]string, provider, key, value string) {
+		if m[provider] == nil {
+			m[provider] = make(map[string]string)
+		}
+		m[provider][key] = value
+	}
+
+	for _, courier := range couriers {
+		code := normalizeCourierCode(courier.Code)
+		add(canonical, "", code, code)
+		add(canonical, "", normalizeCourierCode(courier.Name), code)
+		for _, alias := range courier.Aliases {
+			add(canonical, alias.Provider, normalizeCourierCode(alias.Alias), code)
+			if alias.IsPrimary && alias.Provider != "" {
+				add(providerCodes, alias.Provider, code, normalizeCourierCode(alias.Alias))
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.canonical, r.providerCodes = canonical, providerCodes
+	r.mu.Unlock()
+}
+
+// Canonical returns the canonical code of a courier code sent by a client
+func (r *CourierRegistry) Canonical(code string) string {
+	return r.FromProvider("", code)
+}
+
+// CanonicalList canonicalizes a comma separated list of courier codes, dropping blanks and duplicates
+func (r *CourierRegistry) CanonicalList(codes string) string {
+	seen := make(map[string]bool)
+	canonical := make([]string, 0)
+	for _, code := range strings.Split(codes, ",") {
+		code = r.Canonical(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		canonical = append(canonical, code)
+	}
+	return strings.Join(canonical, ",")
+}
+
+// FromProvider returns the canonical code of a courier code returned by a provider. Provider aliases are
+// tried first, then the aliases shared with clients.
+func (r *CourierRegistry) FromProvider(provider, code string) string {
+	code = normalizeCourierCode(code)
+	if r == nil {
+		return code
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if canonical, ok := r.canonical[provider][code]; ok {
+		return canonical
+	}
+	if canonical, ok := r.canonical[""][code]; ok {
+		return canonical
+	}
+	return code
+}
+
+// ProviderCode returns the code a provider expects for a canonical courier code
+func (r *CourierRegistry) ProviderCode(provider, code string) string {
+	if r == nil {
+		return code
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if providerCode, ok := r.providerCodes[provider][code]; ok {
+		return providerCode
+	}
+	return code
+}
+
+func normalizeCourierCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
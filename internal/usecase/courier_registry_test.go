@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"testing"
+)
+
+func newTestCourierRegistry() *CourierRegistry {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	registry := NewCourierRegistry(nil, log, nil, nil, 0)
+	registry.load(defaultCouriers())
+	return registry
+}
+
+func TestCourierRegistryCanonicalList(t *testing.T) {
+	registry := newTestCourierRegistry()
+
+	tests := []struct {
+		name  string
+		codes string
+		want  string
+	}{
+		{"canonical codes", "jne,sicepat", "jne,sicepat"},
+		{"aliases and names", "J&T Express, si cepat,GoSend", "jnt,sicepat,gojek"},
+		{"blanks and duplicates dropped", "jnt,, j&t ,JT,jnt", "jnt"},
+		{"unknown codes lowercased", "JNE,Paxel", "jne,paxel"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.CanonicalList(tt.codes); got != tt.want {
+				t.Errorf("CanonicalList(%q) = %q, want %q", tt.codes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourierRegistryProviderCode(t *testing.T) {
+	registry := newTestCourierRegistry()
+
+	tests := []struct {
+		name     string
+		provider string
+		code     string
+		want     string
+	}{
+		{"primary alias", ProviderBiteship, "jnt", "jnt"},
+		{"unknown courier passes through", ProviderBiteship, "paxel", "paxel"},
+		{"unknown provider passes through", "shipper", "jnt", "jnt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.ProviderCode(tt.provider, tt.code); got != tt.want {
+				t.Errorf("ProviderCode(%q, %q) = %q, want %q", tt.provider, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourierRegistryFromProvider(t *testing.T) {
+	registry := newTestCourierRegistry()
+
+	tests := []struct {
+		name     string
+		provider string
+		code     string
+		want     string
+	}{
+		{"provider alias", ProviderBiteship, "J&T", "jnt"},
+		{"alias shared with clients", ProviderBiteship, "Lion Parcel", "lion"},
+		{"client alias", "", "jt", "jnt"},
+		{"courier name", "", "Ninja Xpress", "ninja"},
+		{"unknown code lowercased", ProviderBiteship, " Paxel ", "paxel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.FromProvider(tt.provider, tt.code); got != tt.want {
+				t.Errorf("FromProvider(%q, %q) = %q, want %q", tt.provider, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourierRegistryNil(t *testing.T) {
+	var registry *CourierRegistry
+	if got := registry.FromProvider(ProviderBiteship, " JNT "); got != "jnt" {
+		t.Errorf("FromProvider on a nil registry = %q, want %q", got, "jnt")
+	}
+	if got := registry.ProviderCode(ProviderBiteship, "jnt"); got != "jnt" {
+		t.Errorf("ProviderCode on a nil registry = %q, want %q", got, "jnt")
+	}
+}
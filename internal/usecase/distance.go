@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"math"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+)
+
+const earthRadiusKm = 6371.0
+
+// coordinateOf returns the requested coordinate, or the coordinate of the resolved area when none was requested
+func coordinateOf(latitude, longitude *float64, area *entity.Area) *model.Coordinate {
+	if latitude != nil && longitude != nil {
+		return &model.Coordinate{Latitude: *latitude, Longitude: *longitude}
+	}
+	if area != nil && (area.Latitude != 0 || area.Longitude != 0) {
+		return &model.Coordinate{Latitude: area.Latitude, Longitude: area.Longitude}
+	}
+	return nil
+}
+
+// distanceKm returns the great-circle distance between two coordinates using the haversine formula
+func distanceKm(a, b model.Coordinate) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"math"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b model.Coordinate
+		want float64
+	}{
+		{"same point", model.Coordinate{Latitude: -6.2088, Longitude: 106.8456}, model.Coordinate{Latitude: -6.2088, Longitude: 106.8456}, 0},
+		{"jakarta to bandung", model.Coordinate{Latitude: -6.2088, Longitude: 106.8456}, model.Coordinate{Latitude: -6.9175, Longitude: 107.6191}, 116.236},
+		{"one degree on the equator", model.Coordinate{}, model.Coordinate{Longitude: 1}, 111.195},
+		{"across the antimeridian", model.Coordinate{Longitude: 179.5}, model.Coordinate{Longitude: -179.5}, 111.195},
+		{"pole to pole", model.Coordinate{Latitude: 90}, model.Coordinate{Latitude: -90}, 20015.087},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := distanceKm(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("distanceKm() = %v, want %v", got, tt.want)
+			}
+			if got := distanceKm(tt.b, tt.a); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("distanceKm() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoordinateOf(t *testing.T) {
+	latitude, longitude := -6.2088, 106.8456
+	area := &entity.Area{Latitude: -6.9175, Longitude: 107.6191}
+
+	tests := []struct {
+		name      string
+		latitude  *float64
+		longitude *float64
+		area      *entity.Area
+		want      *model.Coordinate
+	}{
+		{"requested coordinate wins", &latitude, &longitude, area, &model.Coordinate{Latitude: latitude, Longitude: longitude}},
+		{"area coordinate as fallback", nil, nil, area, &model.Coordinate{Latitude: -6.9175, Longitude: 107.6191}},
+		{"partial coordinate ignored", &latitude, nil, area, &model.Coordinate{Latitude: -6.9175, Longitude: 107.6191}},
+		{"area without coordinate", nil, nil, &entity.Area{}, nil},
+		{"no area", nil, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coordinateOf(tt.latitude, tt.longitude, tt.area)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("coordinateOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"math"
+	"shipping-gateway/internal/model"
+	"strings"
+)
+
+// FeeSchedule computes a fee as a percentage of a base amount, bounded below by a minimum, plus a flat fee
+type FeeSchedule struct {
+	Rate    float64 `mapstructure:"rate"`    // Fraction of the base amount, e.g. 0.002 for 0.2%
+	Minimum int     `mapstructure:"minimum"` // Minimum fee before the flat fee is added
+	Flat    int     `mapstructure:"flat"`    // Fixed administration fee
+}
+
+// Calculate returns the fee for the given base amount
+func (f FeeSchedule) Calculate(base int) int {
+	fee := int(math.Ceil(float64(base) * f.Rate))
+	return max(fee, f.Minimum) + f.Flat
+}
+
+// FeeSchedules holds a default schedule with per courier overrides
+type FeeSchedules struct {
+	Default  FeeSchedule            `mapstructure:"default"`
+	Couriers map[string]FeeSchedule `mapstructure:"couriers"`
+}
+
+// For returns the schedule used by the given courier
+func (f FeeSchedules) For(courierCode string) FeeSchedule {
+	if schedule, ok := f.Couriers[strings.ToLower(courierCode)]; ok {
+		return schedule
+	}
+	return f.Default
+}
+
+// applyFees adds insurance and COD fees to each price and fills in its breakdown. Fees reported by the
+// provider take precedence over the configured schedules, and are dropped when the option was not requested.
+func applyFees(prices []model.CourierPrice, req *model.CourierRateRequest, insurance, cod FeeSchedules) {
+	insuredValue := req.InsuredValue()
+	for i := range prices {
+		price := &prices[i]
+		breakdown := model.PriceBreakdown{Shipping: price.Price}
+
+		fees := make([]model.PriceFee, 0, len(price.Fees))
+		for _, fee := range price.Fees {
+			switch {
+			case fee.Type == model.PriceFeeTypeInsurance && req.Insurance:
+				breakdown.Insurance = fee.Amount
+			case fee.Type == model.PriceFeeTypeCOD && req.COD:
+				breakdown.CODFee = fee.Amount
+			case fee.Type == model.PriceFeeTypeInsurance, fee.Type == model.PriceFeeTypeCOD:
+				continue
+			case fee.Type == model.PriceFeeTypeTax:
+				breakdown.Tax += fee.Amount
+			}
+			fees = append(fees, fee)
+		}
+
+		if req.Insurance && breakdown.Insurance == 0 && price.Capabilities.Insurance {
+			breakdown.Insurance = insurance.For(price.CourierCode).Calculate(insuredValue)
+			fees = append(fees, model.PriceFee{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: breakdown.Insurance})
+		}
+
+		// COD collects the goods value and the shipping cost from the recipient
+		if req.COD && breakdown.CODFee == 0 && price.Capabilities.CashOnDelivery {
+			breakdown.CODFee = cod.For(price.CourierCode).Calculate(insuredValue + price.Price)
+			fees = append(fees, model.PriceFee{Type: model.PriceFeeTypeCOD, Name: "cash_on_delivery", Amount: breakdown.CODFee})
+		}
+
+		breakdown.Total = breakdown.Shipping + breakdown.Insurance + breakdown.CODFee + breakdown.Tax
+		price.Fees = fees
+		price.Breakdown = breakdown
+	}
+}
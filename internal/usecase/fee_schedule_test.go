@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"reflect"
+	"shipping-gateway/internal/model"
+	"testing"
+)
+
+func TestFeeScheduleCalculate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule FeeSchedule
+		base     int
+		want     int
+	}{
+		{"rate rounded up", FeeSchedule{Rate: 0.002}, 100001, 201},
+		{"minimum applies", FeeSchedule{Rate: 0.002, Minimum: 5000}, 100000, 5000},
+		{"flat added after minimum", FeeSchedule{Rate: 0.03, Minimum: 2500, Flat: 1000}, 10000, 3500},
+		{"zero base", FeeSchedule{Rate: 0.03, Flat: 1000}, 0, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Calculate(tt.base); got != tt.want {
+				t.Errorf("Calculate(%d) = %d, want %d", tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFees(t *testing.T) {
+	insurance := FeeSchedules{
+		Default:  FeeSchedule{Rate: 0.002, Minimum: 5000},
+		Couriers: map[string]FeeSchedule{"jne": {Rate: 0.005}},
+	}
+	cod := FeeSchedules{Default: FeeSchedule{Rate: 0.03, Flat: 1000}}
+	tax := model.PriceFee{Type: model.PriceFeeTypeTax, Name: "VAT", Amount: 1100}
+	providerInsurance := model.PriceFee{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: 3000}
+	providerCOD := model.PriceFee{Type: model.PriceFeeTypeCOD, Name: "cash_on_delivery", Amount: 4000}
+	capable := model.CourierCapabilities{Insurance: true, CashOnDelivery: true}
+
+	tests := []struct {
+		name          string
+		price         model.CourierPrice
+		insurance     bool
+		cod           bool
+		declaredValue int
+		wantBreakdown model.PriceBreakdown
+		wantFees      []model.PriceFee
+	}{
+		{
+			name:          "tax kept and unrequested provider fees dropped",
+			price:         model.CourierPrice{CourierCode: "sicepat", Price: 10000, Fees: []model.PriceFee{tax, providerInsurance, providerCOD}, Capabilities: capable},
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, Tax: 1100, Total: 11100},
+			wantFees:      []model.PriceFee{tax},
+		},
+		{
+			name:          "provider fees take precedence",
+			price:         model.CourierPrice{CourierCode: "sicepat", Price: 10000, Fees: []model.PriceFee{providerInsurance, providerCOD}, Capabilities: capable},
+			insurance:     true,
+			cod:           true,
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, Insurance: 3000, CODFee: 4000, Total: 17000},
+			wantFees:      []model.PriceFee{providerInsurance, providerCOD},
+		},
+		{
+			name:          "insurance from the schedule minimum",
+			price:         model.CourierPrice{CourierCode: "sicepat", Price: 10000, Capabilities: capable},
+			insurance:     true,
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, Insurance: 5000, Total: 15000},
+			wantFees:      []model.PriceFee{{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: 5000}},
+		},
+		{
+			name:          "insurance from the courier schedule on the declared value",
+			price:         model.CourierPrice{CourierCode: "JNE", Price: 10000, Capabilities: capable},
+			insurance:     true,
+			declaredValue: 1000000,
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, Insurance: 5000, Total: 15000},
+			wantFees:      []model.PriceFee{{Type: model.PriceFeeTypeInsurance, Name: "insurance", Amount: 5000}},
+		},
+		{
+			name:          "cod computed on goods plus shipping",
+			price:         model.CourierPrice{CourierCode: "sicepat", Price: 10000, Capabilities: capable},
+			cod:           true,
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, CODFee: 7300, Total: 17300},
+			wantFees:      []model.PriceFee{{Type: model.PriceFeeTypeCOD, Name: "cash_on_delivery", Amount: 7300}},
+		},
+		{
+			name:          "no fees for unsupported options",
+			price:         model.CourierPrice{CourierCode: "sicepat", Price: 10000},
+			insurance:     true,
+			cod:           true,
+			wantBreakdown: model.PriceBreakdown{Shipping: 10000, Total: 10000},
+			wantFees:      []model.PriceFee{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &model.CourierRateRequest{
+				Items:         []model.ItemRequest{{Price: 100000, Quantity: 2}},
+				Insurance:     tt.insurance,
+				COD:           tt.cod,
+				DeclaredValue: tt.declaredValue,
+			}
+			prices := []model.CourierPrice{tt.price}
+			applyFees(prices, req, insurance, cod)
+
+			if prices[0].Breakdown != tt.wantBreakdown {
+				t.Errorf("Breakdown = %+v, want %+v", prices[0].Breakdown, tt.wantBreakdown)
+			}
+			if !reflect.DeepEqual(prices[0].Fees, tt.wantFees) {
+				t.Errorf("Fees = %+v, want %+v", prices[0].Fees, tt.wantFees)
+			}
+		})
+	}
+}
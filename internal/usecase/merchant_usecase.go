@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/model/converter"
+	"shipping-gateway/internal/repository"
+	"shipping-gateway/internal/secret"
+)
+
+type MerchantUseCase struct {
+	DB           *gorm.DB
+	Log          *logrus.Logger
+	MerchantRepo *repository.MerchantRepository
+	Cipher       *secret.Cipher
+	Tenants      *TenantRegistry
+}
+
+func NewMerchantUseCase(db *gorm.DB, log *logrus.Logger, merchantRepo *repository.MerchantRepository, cipher *secret.Cipher,
+	tenants *TenantRegistry) *MerchantUseCase {
+	return &MerchantUseCase{
+		DB:           db,
+		Log:          log,
+		MerchantRepo: merchantRepo,
+		Cipher:       cipher,
+		Tenants:      tenants,
+	}
+}
+
+func (uc *MerchantUseCase) CreateMerchant(ctx context.Context, req model.MerchantCreateRequest) (*model.ServiceResponse, *model.MerchantResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	credentials, err := uc.encrypt(req.Credentials)
+	if err != nil {
+		log.Errorf("Error encrypting merchant credentials: %v", err)
+		return model.DefaultError("Failed to create merchant", nil), nil
+	}
+
+	merchant := &entity.Merchant{
+		Name:                    req.Name,
+		Enabled:                 true,
+		Credentials:             credentials,
+		DefaultCouriers:         req.DefaultCouriers,
+		DefaultOriginPostalCode: req.DefaultOriginPostalCode,
+	}
+	if err = uc.MerchantRepo.Create(uc.DB, merchant); err != nil {
+		log.Errorf("Error creating merchant: %v", err)
+		return model.DefaultError("Failed to create merchant", nil), nil
+	}
+
+	resp := converter.MerchantToResponse(merchant, req.Credentials)
+	return &model.ServiceResponse{StatusCode: http.StatusCreated, Message: "Merchant created"}, &resp
+}
+
+func (uc *MerchantUseCase) ListMerchants(ctx context.Context) (*model.ServiceResponse, []model.MerchantResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	merchants, err := uc.MerchantRepo.FindAll(uc.DB)
+	if err != nil {
+		log.Errorf("Error listing merchants: %v", err)
+		return model.DefaultError("Failed to list merchants", nil), nil
+	}
+
+	resp := make([]model.MerchantResponse, 0, len(merchants))
+	for i := range merchants {
+		credentials, err := uc.Tenants.Credentials(&merchants[i])
+		if err != nil {
+			log.Warnf("Error reading credentials: %v", err)
+		}
+		resp = append(resp, converter.MerchantToResponse(&merchants[i], credentials))
+	}
+	return model.Success(), resp
+}
+
+func (uc *MerchantUseCase) GetMerchant(ctx context.Context, id string) (*model.ServiceResponse, *model.MerchantResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, merchant := uc.findMerchant(ctx, id)
+	if merchant == nil {
+		return ucResp, nil
+	}
+
+	credentials, err := uc.Tenants.Credentials(merchant)
+	if err != nil {
+		log.Warnf("Error reading credentials: %v", err)
+	}
+	resp := converter.MerchantToResponse(merchant, credentials)
+	return model.Success(), &resp
+}
+
+// UpdateMerchant changes the settings or credentials of a merchant. Its pooled provider clients are dropped so
+// the change applies to the next request.
+func (uc *MerchantUseCase) UpdateMerchant(ctx context.Context, id string, req model.MerchantUpdateRequest) (*model.ServiceResponse, *model.MerchantResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, merchant := uc.findMerchant(ctx, id)
+	if merchant == nil {
+		return ucResp, nil
+	}
+
+	if req.Name != nil {
+		merchant.Name = *req.Name
+	}
+	if req.Enabled != nil {
+		merchant.Enabled = *req.Enabled
+	}
+	if req.DefaultCouriers != nil {
+		merchant.DefaultCouriers = *req.DefaultCouriers
+	}
+	if req.DefaultOriginPostalCode != nil {
+		merchant.DefaultOriginPostalCode = *req.DefaultOriginPostalCode
+	}
+	if req.Credentials != nil {
+		credentials, err := uc.encrypt(*req.Credentials)
+		if err != nil {
+			log.Errorf("Error encrypting credentials of merchant %s: %v", id, err)
+			return model.DefaultError("Failed to update merchant", nil), nil
+		}
+		merchant.Credentials = credentials
+	}
+
+	if err := uc.MerchantRepo.Update(uc.DB, merchant); err != nil {
+		log.Errorf("Error updating merchant %s: %v", id, err)
+		return model.DefaultError("Failed to update merchant", nil), nil
+	}
+	uc.Tenants.Invalidate(id)
+
+	return uc.GetMerchant(ctx, id)
+}
+
+// RotateCredentials re-encrypts the credentials of every merchant not sealed with the current master key yet,
+// and returns how many were re-encrypted. Once it completes the previous master keys can leave the keyring.
+func (uc *MerchantUseCase) RotateCredentials(ctx context.Context) (int, error) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	merchants, err := uc.MerchantRepo.FindAll(uc.DB)
+	if err != nil {
+		return 0, fmt.Errorf("error listing merchants: %w", err)
+	}
+
+	rotated := 0
+	for i := range merchants {
+		merchant := &merchants[i]
+		if !uc.Cipher.NeedsRotation(merchant.Credentials) {
+			continue
+		}
+
+		plaintext, err := uc.Cipher.Decrypt(merchant.Credentials)
+		if err != nil {
+			return rotated, fmt.Errorf("error decrypting credentials of merchant %s: %w", merchant.ID, err)
+		}
+		if merchant.Credentials, err = uc.Cipher.Encrypt(plaintext); err != nil {
+			return rotated, fmt.Errorf("error encrypting credentials of merchant %s: %w", merchant.ID, err)
+		}
+		if err = uc.MerchantRepo.UpdateCredentials(uc.DB, merchant); err != nil {
+			return rotated, fmt.Errorf("error saving credentials of merchant %s: %w", merchant.ID, err)
+		}
+
+		uc.Tenants.Invalidate(merchant.ID)
+		log.Infof("Re-encrypted credentials of merchant %s", merchant.ID)
+		rotated++
+	}
+	return rotated, nil
+}
+
+func (uc *MerchantUseCase) findMerchant(ctx context.Context, id string) (*model.ServiceResponse, *entity.Merchant) {
+	merchant, err := uc.MerchantRepo.FindByID(uc.DB, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.NotFound("Merchant not found"), nil
+	}
+	if err != nil {
+		uc.Log.WithField("traceId", ctx.Value("traceId")).Errorf("Error getting merchant %s: %v", id, err)
+		return model.DefaultError("Failed to get merchant", nil), nil
+	}
+	return model.Success(), merchant
+}
+
+func (uc *MerchantUseCase) encrypt(credentials model.ProviderCredentials) (string, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+	return uc.Cipher.Encrypt(plaintext)
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"math"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"sort"
+)
+
+// packUnit is a single unit of an item to be packed
+type packUnit struct {
+	index  int    // Index of the item in the request
+	dims   [3]int // Dimensions sorted from largest to smallest
+	volume int
+	weight int
+}
+
+// packedBox is a box being filled with units. The totals are kept as units are added, so checking whether
+// another unit fits does not rescan the box.
+type packedBox struct {
+	box     entity.Box
+	units   []packUnit
+	maxDims [3]int // Largest unit dimension on each sorted axis
+	volume  int
+	weight  int
+}
+
+func newPackedBox(box entity.Box, unit packUnit) *packedBox {
+	return &packedBox{box: box, units: []packUnit{unit}, maxDims: unit.dims, volume: unit.volume, weight: unit.weight}
+}
+
+// fitsWith reports whether the box can hold its units and one more
+func (p *packedBox) fitsWith(unit packUnit, fillFactor float64) bool {
+	return canHold(p.box, len(p.units)+1, maxDims(p.maxDims, unit.dims), p.volume+unit.volume, p.weight+unit.weight, fillFactor)
+}
+
+func (p *packedBox) add(unit packUnit) {
+	p.units = append(p.units, unit)
+	p.maxDims = maxDims(p.maxDims, unit.dims)
+	p.volume += unit.volume
+	p.weight += unit.weight
+}
+
+func maxDims(a, b [3]int) [3]int {
+	return [3]int{max(a[0], b[0]), max(a[1], b[1]), max(a[2], b[2])}
+}
+
+func sortedDims(length, width, height int) [3]int {
+	dims := []int{length, width, height}
+	sort.Sort(sort.Reverse(sort.IntSlice(dims)))
+	return [3]int{dims[0], dims[1], dims[2]}
+}
+
+// fitsInside reports whether a unit fits in a box in some axis aligned orientation
+func fitsInside(unit [3]int, box entity.Box) bool {
+	boxDims := sortedDims(box.Length, box.Width, box.Height)
+	return unit[0] <= boxDims[0] && unit[1] <= boxDims[1] && unit[2] <= boxDims[2]
+}
+
+// canHold reports whether the box can hold count units, given the largest unit dimension on each sorted axis
+// and their summed volume and weight. The summed volume may use up to fillFactor of the box volume since units
+// rarely tessellate perfectly, except for a single unit that fits on its own.
+func canHold(box entity.Box, count int, dims [3]int, volume, weight int, fillFactor float64) bool {
+	// every unit fits when the largest dimension on each sorted axis does
+	if !fitsInside(dims, box) {
+		return false
+	}
+	if box.MaxWeight > 0 && weight > box.MaxWeight {
+		return false
+	}
+	if count == 1 {
+		return true
+	}
+	return float64(volume) <= float64(box.Length*box.Width*box.Height)*fillFactor
+}
+
+// packItems packs the items into the given box types and returns the best packing for the strategy.
+// It first fills boxes first-fit by decreasing unit volume, trying every box type as the preferred type
+// for new boxes, then shrinks each filled box to the cheapest box type that still holds its content.
+// Units that fit no box are shipped in their own packaging.
+func packItems(items []model.ItemRequest, boxes []entity.Box, strategy model.PackingStrategy, fillFactor float64) model.PackingResult {
+	units := make([]packUnit, 0)
+	for i, item := range items {
+		for q := 0; q < max(item.Quantity, 1); q++ {
+			units = append(units, packUnit{
+				index:  i,
+				dims:   sortedDims(item.Length, item.Width, item.Height),
+				volume: item.Length * item.Width * item.Height,
+				weight: item.Weight,
+			})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].volume > units[j].volume
+	})
+
+	// smallest and cheapest first, used both to shrink boxes and to pick the largest fitting box
+	sort.SliceStable(boxes, func(i, j int) bool {
+		if boxes[i].Cost != boxes[j].Cost {
+			return boxes[i].Cost < boxes[j].Cost
+		}
+		return boxes[i].Length*boxes[i].Width*boxes[i].Height < boxes[j].Length*boxes[j].Width*boxes[j].Height
+	})
+
+	var best *model.PackingResult
+	for preferred := -1; preferred < len(boxes); preferred++ {
+		packed, unpacked := fillBoxes(units, boxes, preferred, fillFactor)
+		result := toPackingResult(items, packed, unpacked, boxes, strategy, fillFactor)
+		if best == nil || betterPacking(result, *best, strategy) {
+			best = &result
+		}
+	}
+	return *best
+}
+
+// fillBoxes places each unit in the first open box that can hold it, opening a box of the preferred type
+// when possible or else the largest type that fits the unit. A negative preferred always opens the largest.
+func fillBoxes(units []packUnit, boxes []entity.Box, preferred int, fillFactor float64) ([]*packedBox, []packUnit) {
+	packed := make([]*packedBox, 0)
+	unpacked := make([]packUnit, 0)
+
+	for _, unit := range units {
+		placed := false
+		for _, open := range packed {
+			if open.fitsWith(unit, fillFactor) {
+				open.add(unit)
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+
+		boxIndex := -1
+		if preferred >= 0 && canHold(boxes[preferred], 1, unit.dims, unit.volume, unit.weight, fillFactor) {
+			boxIndex = preferred
+		} else {
+			largest := 0
+			for i, box := range boxes {
+				volume := box.Length * box.Width * box.Height
+				if volume > largest && canHold(box, 1, unit.dims, unit.volume, unit.weight, fillFactor) {
+					largest = volume
+					boxIndex = i
+				}
+			}
+		}
+
+		if boxIndex < 0 {
+			unpacked = append(unpacked, unit)
+			continue
+		}
+		packed = append(packed, newPackedBox(boxes[boxIndex], unit))
+	}
+
+	return packed, unpacked
+}
+
+func toPackingResult(items []model.ItemRequest, packed []*packedBox, unpacked []packUnit, boxes []entity.Box,
+	strategy model.PackingStrategy, fillFactor float64) model.PackingResult {
+	result := model.PackingResult{Strategy: strategy, Packages: make([]model.PackedBox, 0, len(packed)+len(unpacked))}
+
+	for _, open := range packed {
+		// shrink to the cheapest, then smallest, box type holding the same content
+		for _, box := range boxes {
+			if canHold(box, len(open.units), open.maxDims, open.volume, open.weight, fillFactor) {
+				open.box = box
+				break
+			}
+		}
+
+		boxVolume := open.box.Length * open.box.Width * open.box.Height
+		pkg := model.PackedBox{
+			BoxID:   open.box.ID,
+			BoxName: open.box.Name,
+			Metrics: model.Metrics{
+				Length: open.box.Length,
+				Width:  open.box.Width,
+				Height: open.box.Height,
+				Weight: open.weight + open.box.BoxWeight,
+			},
+			Cost:     open.box.Cost,
+			FillRate: math.Round(float64(open.volume)/float64(max(boxVolume, 1))*10000) / 10000,
+			Items:    groupPackedItems(items, open.units),
+		}
+		for _, unit := range open.units {
+			pkg.Value += items[unit.index].Price
+		}
+		result.Packages = append(result.Packages, pkg)
+		result.TotalCost += pkg.Cost
+	}
+
+	for _, unit := range unpacked {
+		item := items[unit.index]
+		result.Packages = append(result.Packages, model.PackedBox{
+			BoxName:  item.Name,
+			Metrics:  item.Metrics,
+			Value:    item.Price,
+			FillRate: 1,
+			Items:    []model.PackedItem{{Name: item.Name, Quantity: 1}},
+		})
+	}
+
+	return result
+}
+
+func groupPackedItems(items []model.ItemRequest, units []packUnit) []model.PackedItem {
+	quantities := make(map[int]int)
+	order := make([]int, 0)
+	for _, unit := range units {
+		if _, ok := quantities[unit.index]; !ok {
+			order = append(order, unit.index)
+		}
+		quantities[unit.index]++
+	}
+
+	packedItems := make([]model.PackedItem, 0, len(order))
+	for _, index := range order {
+		packedItems = append(packedItems, model.PackedItem{Name: items[index].Name, Quantity: quantities[index]})
+	}
+	return packedItems
+}
+
+// betterPacking reports whether a is a better packing than b for the strategy
+func betterPacking(a, b model.PackingResult, strategy model.PackingStrategy) bool {
+	if strategy == model.PackingStrategyCheapest {
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost < b.TotalCost
+		}
+		return len(a.Packages) < len(b.Packages)
+	}
+
+	if len(a.Packages) != len(b.Packages) {
+		return len(a.Packages) < len(b.Packages)
+	}
+	return a.TotalCost < b.TotalCost
+}
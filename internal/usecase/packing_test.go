@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"fmt"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"strings"
+	"testing"
+)
+
+// describePacking summarises a packing as "box[item×quantity ...]" per package
+func describePacking(result model.PackingResult) string {
+	packages := make([]string, 0, len(result.Packages))
+	for _, pkg := range result.Packages {
+		items := make([]string, 0, len(pkg.Items))
+		for _, item := range pkg.Items {
+			items = append(items, fmt.Sprintf("%s×%d", item.Name, item.Quantity))
+		}
+		packages = append(packages, fmt.Sprintf("%s[%s]", pkg.BoxName, strings.Join(items, " ")))
+	}
+	return strings.Join(packages, " ")
+}
+
+func testItem(name string, length, width, height, weight, quantity int) model.ItemRequest {
+	return model.ItemRequest{Name: name, Price: 10000, Quantity: quantity,
+		Metrics: model.Metrics{Length: length, Width: width, Height: height, Weight: weight}}
+}
+
+func TestPackItems(t *testing.T) {
+	small := entity.Box{ID: 1, Name: "small", Length: 20, Width: 20, Height: 10, MaxWeight: 1000, Cost: 1000}
+	medium := entity.Box{ID: 2, Name: "medium", Length: 30, Width: 30, Height: 20, MaxWeight: 5000, Cost: 2000}
+	large := entity.Box{ID: 3, Name: "large", Length: 50, Width: 40, Height: 40, Cost: 4000}
+	cube := entity.Box{ID: 4, Name: "cube", Length: 30, Width: 30, Height: 30, Cost: 1000}
+	crate := entity.Box{ID: 5, Name: "crate", Length: 100, Width: 100, Height: 100, Cost: 10000}
+
+	tests := []struct {
+		name       string
+		items      []model.ItemRequest
+		boxes      []entity.Box
+		strategy   model.PackingStrategy
+		fillFactor float64
+		want       string
+		wantCost   int
+	}{
+		{
+			name:       "item larger than every box ships in its own packaging",
+			items:      []model.ItemRequest{testItem("rod", 120, 5, 5, 800, 1), testItem("cap", 10, 10, 5, 100, 1)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.9,
+			want:       "small[cap×1] rod[rod×1]",
+			wantCost:   1000,
+		},
+		{
+			name:       "single unit fits exactly in any orientation",
+			items:      []model.ItemRequest{testItem("tray", 10, 20, 20, 500, 1)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.9,
+			want:       "small[tray×1]",
+			wantCost:   1000,
+		},
+		{
+			name:       "units filling the box exactly at full fill factor",
+			items:      []model.ItemRequest{testItem("tile", 20, 20, 5, 200, 2)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 1,
+			want:       "small[tile×2]",
+			wantCost:   1000,
+		},
+		{
+			name:       "units filling the box exactly above the fill factor",
+			items:      []model.ItemRequest{testItem("tile", 20, 20, 5, 200, 2)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.9,
+			want:       "medium[tile×2]",
+			wantCost:   2000,
+		},
+		{
+			name:       "weight limited box",
+			items:      []model.ItemRequest{testItem("weight", 10, 10, 5, 1500, 1)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.9,
+			want:       "medium[weight×1]",
+			wantCost:   2000,
+		},
+		{
+			name:       "volume limited box",
+			items:      []model.ItemRequest{testItem("block", 10, 10, 10, 100, 4)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.8,
+			want:       "medium[block×4]",
+			wantCost:   2000,
+		},
+		{
+			name:       "volume limit not reached",
+			items:      []model.ItemRequest{testItem("block", 10, 10, 10, 100, 3)},
+			boxes:      []entity.Box{small, medium, large},
+			fillFactor: 0.8,
+			want:       "small[block×3]",
+			wantCost:   1000,
+		},
+		{
+			name:       "fewest boxes prefers the largest box",
+			items:      []model.ItemRequest{testItem("cube", 30, 30, 30, 1000, 2)},
+			boxes:      []entity.Box{crate, cube},
+			strategy:   model.PackingStrategyFewest,
+			fillFactor: 0.9,
+			want:       "crate[cube×2]",
+			wantCost:   10000,
+		},
+		{
+			name:       "cheapest prefers a smaller box type",
+			items:      []model.ItemRequest{testItem("cube", 30, 30, 30, 1000, 2)},
+			boxes:      []entity.Box{crate, cube},
+			strategy:   model.PackingStrategyCheapest,
+			fillFactor: 0.9,
+			want:       "cube[cube×1] cube[cube×1]",
+			wantCost:   2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := packItems(tt.items, tt.boxes, tt.strategy, tt.fillFactor)
+			if got := describePacking(result); got != tt.want {
+				t.Errorf("packItems() = %s, want %s", got, tt.want)
+			}
+			if result.TotalCost != tt.wantCost {
+				t.Errorf("TotalCost = %d, want %d", result.TotalCost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestPackItemsPackageWeight(t *testing.T) {
+	box := entity.Box{ID: 1, Name: "small", Length: 20, Width: 20, Height: 10, BoxWeight: 150, Cost: 1000}
+	result := packItems([]model.ItemRequest{testItem("tile", 20, 20, 5, 200, 2)}, []entity.Box{box}, model.PackingStrategyFewest, 1)
+
+	pkg := result.Packages[0]
+	if pkg.Weight != 550 || pkg.Value != 20000 || pkg.FillRate != 1 {
+		t.Errorf("package = %+v, want weight 550, value 20000 and fill rate 1", pkg)
+	}
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+)
+
+type PackingUseCase struct {
+	DB         *gorm.DB
+	Log        *logrus.Logger
+	BoxRepo    *repository.BoxRepository
+	FillFactor float64 // Share of a box volume that can be filled by multiple items
+}
+
+func NewPackingUseCase(db *gorm.DB, log *logrus.Logger, boxRepo *repository.BoxRepository, fillFactor float64) *PackingUseCase {
+	return &PackingUseCase{
+		DB:         db,
+		Log:        log,
+		BoxRepo:    boxRepo,
+		FillFactor: fillFactor,
+	}
+}
+
+// Pack bin-packs the items into the boxes configured for the merchant of the request, or the default boxes when
+// the merchant has none
+func (uc *PackingUseCase) Pack(ctx context.Context, options model.PackingOptions, items []model.ItemRequest) (*model.ServiceResponse, *model.PackingResult) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	if len(items) == 0 {
+		return model.BadRequest("Items are required for packing", nil), nil
+	}
+	units := 0
+	for _, item := range items {
+		units += max(item.Quantity, 1)
+	}
+	if units > model.MaxPackingUnits {
+		return model.BadRequest(fmt.Sprintf("Items must not contain more than %d units when packing", model.MaxPackingUnits), nil), nil
+	}
+
+	merchantID := TenantID(ctx)
+	boxes, err := uc.BoxRepo.FindByMerchant(uc.DB, merchantID)
+	if err != nil {
+		log.Errorf("Error loading boxes for merchant %s: %v", merchantID, err)
+		return model.DefaultError("Failed to load boxes", nil), nil
+	}
+	if len(boxes) == 0 {
+		return model.NotFound("No boxes configured for packing"), nil
+	}
+
+	strategy := options.Strategy
+	if strategy == "" {
+		strategy = model.PackingStrategyFewest
+	}
+
+	result := packItems(items, boxes, strategy, uc.FillFactor)
+	log.Debugf("Packed %d items into %d packages using %s strategy", len(items), len(result.Packages), strategy)
+	return model.Success(), &result
+}
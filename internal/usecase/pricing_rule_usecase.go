@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"math"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"strings"
+)
+
+type PricingRuleUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	PricingRuleRepo *repository.PricingRuleRepository
+}
+
+func NewPricingRuleUseCase(db *gorm.DB, log *logrus.Logger, pricingRuleRepo *repository.PricingRuleRepository) *PricingRuleUseCase {
+	return &PricingRuleUseCase{
+		DB:              db,
+		Log:             log,
+		PricingRuleRepo: pricingRuleRepo,
+	}
+}
+
+// PricingContext describes the shipment the prices are quoted for
+type PricingContext struct {
+	DestinationProvince string // Province of the destination area
+	CartValue           int    // Total value of the items in the cart
+}
+
+// ApplyRules applies every matching enabled platform rule and then every matching enabled rule of the tenant,
+// each in priority order, to each price.
+// The provider price is kept as OriginalPrice and Price holds the final amount.
+func (uc *PricingRuleUseCase) ApplyRules(ctx context.Context, pc PricingContext, prices []model.CourierPrice) error {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	rules, err := uc.PricingRuleRepo.FindEnabled(uc.DB, TenantID(ctx))
+	if err != nil {
+		log.Errorf("Error loading pricing rules: %v", err)
+		return err
+	}
+
+	for i := range prices {
+		price := &prices[i]
+		price.OriginalPrice = price.Price
+		price.AppliedRules = make([]uint, 0)
+
+		for _, rule := range rules {
+			if !ruleMatches(rule, pc, *price) {
+				continue
+			}
+			price.Price = applyRuleAction(rule, price.Price)
+			price.AppliedRules = append(price.AppliedRules, rule.ID)
+		}
+	}
+
+	return nil
+}
+
+func ruleMatches(rule entity.PricingRule, pc PricingContext, price model.CourierPrice) bool {
+	if rule.CourierCode != "" && !strings.EqualFold(rule.CourierCode, price.CourierCode) {
+		return false
+	}
+	if rule.ServiceType != "" && !strings.EqualFold(rule.ServiceType, price.ServiceType) {
+		return false
+	}
+	if rule.DestinationProvince != "" && !strings.EqualFold(rule.DestinationProvince, pc.DestinationProvince) {
+		return false
+	}
+
+	weight := price.Weight.Chargeable
+	if rule.MinWeight > 0 && weight < rule.MinWeight {
+		return false
+	}
+	if rule.MaxWeight > 0 && weight > rule.MaxWeight {
+		return false
+	}
+	if rule.MinCartValue > 0 && pc.CartValue < rule.MinCartValue {
+		return false
+	}
+	if rule.MaxCartValue > 0 && pc.CartValue > rule.MaxCartValue {
+		return false
+	}
+
+	return true
+}
+
+func applyRuleAction(rule entity.PricingRule, price int) int {
+	switch rule.Action {
+	case entity.PricingRuleActionMarkupPercent:
+		price += int(math.Round(float64(price) * rule.Value / 100))
+	case entity.PricingRuleActionFixedFee:
+		price += int(math.Round(rule.Value))
+	case entity.PricingRuleActionCap:
+		price = min(price, int(math.Round(rule.Value)))
+	case entity.PricingRuleActionFree:
+		price = 0
+	}
+	return max(price, 0)
+}
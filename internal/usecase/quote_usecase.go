@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/model/converter"
+	"shipping-gateway/internal/repository"
+	"time"
+)
+
+type QuoteUseCase struct {
+	DB         *gorm.DB
+	Log        *logrus.Logger
+	QuoteRepo  *repository.QuoteRepository
+	TTL        time.Duration
+	SigningKey []byte
+}
+
+func NewQuoteUseCase(db *gorm.DB, log *logrus.Logger, quoteRepo *repository.QuoteRepository, ttl time.Duration, signingKey string) *QuoteUseCase {
+	return &QuoteUseCase{
+		DB:         db,
+		Log:        log,
+		QuoteRepo:  quoteRepo,
+		TTL:        ttl,
+		SigningKey: []byte(signingKey),
+	}
+}
+
+// QuoteInput describes the resolved request a set of prices was quoted for
+type QuoteInput struct {
+	Origin      model.QuoteLocation
+	Destination model.QuoteLocation
+	Items       []model.ItemRequest
+}
+
+// CreateQuotes persists every price as a signed quote and sets its quote ID and expiry
+func (uc *QuoteUseCase) CreateQuotes(ctx context.Context, input QuoteInput, prices []model.CourierPrice) error {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	items, err := json.Marshal(input.Items)
+	if err != nil {
+		return fmt.Errorf("error marshalling quote items: %w", err)
+	}
+
+	expiresAt := time.Now().Add(uc.TTL).Truncate(time.Second)
+	quotes := make([]entity.Quote, 0, len(prices))
+	for i := range prices {
+		id, _ := uuid.NewV7()
+		prices[i].QuoteID = id.String()
+		prices[i].QuoteExpires = &expiresAt
+
+		appliedRules, err := json.Marshal(prices[i].AppliedRules)
+		if err != nil {
+			return fmt.Errorf("error marshalling applied rules: %w", err)
+		}
+		priceDetail, err := json.Marshal(prices[i])
+		if err != nil {
+			return fmt.Errorf("error marshalling quote price: %w", err)
+		}
+
+		quote := entity.Quote{
+			ID:                    prices[i].QuoteID,
+			MerchantID:            TenantID(ctx),
+			CourierCode:           prices[i].CourierCode,
+			ServiceCode:           prices[i].ServiceCode,
+			ServiceType:           prices[i].ServiceType,
+			Currency:              prices[i].Currency,
+			Price:                 prices[i].Price,
+			OriginalPrice:         prices[i].OriginalPrice,
+			Total:                 prices[i].Breakdown.Total,
+			OriginAreaID:          input.Origin.AreaID,
+			OriginPostalCode:      input.Origin.PostalCode,
+			DestinationAreaID:     input.Destination.AreaID,
+			DestinationPostalCode: input.Destination.PostalCode,
+			Items:                 string(items),
+			AppliedRules:          string(appliedRules),
+			PriceDetail:           string(priceDetail),
+			ExpiresAt:             expiresAt,
+		}
+		quote.Signature = uc.sign(&quote)
+		quotes = append(quotes, quote)
+	}
+
+	if err := uc.QuoteRepo.CreateInBatch(uc.DB, quotes); err != nil {
+		log.Errorf("Error saving quotes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ValidateQuote loads a quote and rejects it when it is missing, expired or its content no longer matches
+// its signature. Order creation should call this before accepting a quote ID.
+func (uc *QuoteUseCase) ValidateQuote(ctx context.Context, id string) (*model.ServiceResponse, *entity.Quote) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	// quotes of other tenants are reported missing rather than forbidden so their IDs cannot be probed
+	quote, err := uc.QuoteRepo.FindByID(uc.DB, id)
+	if err == nil && quote.MerchantID != TenantID(ctx) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.NotFound("Quote not found"), nil
+		}
+		log.Errorf("Error finding quote %s: %v", id, err)
+		return model.DefaultError("Failed to get quote", nil), nil
+	}
+
+	if !hmac.Equal([]byte(quote.Signature), []byte(uc.sign(quote))) {
+		log.Warnf("Quote %s failed signature verification", id)
+		return model.UnprocessableEntity("Quote has been tampered with"), nil
+	}
+
+	if time.Now().After(quote.ExpiresAt) {
+		return model.Gone("Quote has expired"), nil
+	}
+
+	return model.Success(), quote
+}
+
+func (uc *QuoteUseCase) GetQuote(ctx context.Context, id string) (*model.ServiceResponse, *model.QuoteResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	ucResp, quote := uc.ValidateQuote(ctx, id)
+	if quote == nil {
+		return ucResp, nil
+	}
+
+	resp, err := converter.QuoteToResponse(quote)
+	if err != nil {
+		log.Errorf("Error converting quote %s to response: %v", id, err)
+		return model.DefaultError("Failed to get quote", nil), nil
+	}
+	return model.Success(), resp
+}
+
+// sign computes the HMAC of every stored field of the quote, so neither its owner nor what the customer is
+// charged can be changed without invalidating it
+func (uc *QuoteUseCase) sign(q *entity.Quote) string {
+	mac := hmac.New(sha256.New, uc.SigningKey)
+	_, _ = fmt.Fprintf(mac, "%s|%s|%s|%s|%s|%s|%d|%d|%d|%s|%s|%s|%s|%s|%s|%s|%d",
+		q.ID, q.MerchantID, q.CourierCode, q.ServiceCode, q.ServiceType, q.Currency, q.Price, q.OriginalPrice, q.Total,
+		q.OriginAreaID, q.OriginPostalCode, q.DestinationAreaID, q.DestinationPostalCode,
+		q.Items, q.AppliedRules, q.PriceDetail, q.ExpiresAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
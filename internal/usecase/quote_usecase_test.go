@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"reflect"
+	"shipping-gateway/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestQuoteSignDetectsTampering(t *testing.T) {
+	uc := &QuoteUseCase{SigningKey: []byte("test-signing-key")}
+	quote := entity.Quote{
+		ID:                    "0190f5a2-7c1e-7000-8000-000000000001",
+		MerchantID:            "merchant-1",
+		CourierCode:           "jne",
+		ServiceCode:           "reg",
+		ServiceType:           "standard",
+		Currency:              "IDR",
+		Price:                 9000,
+		OriginalPrice:         10000,
+		Total:                 12000,
+		OriginAreaID:          "IDNP6",
+		OriginPostalCode:      "12440",
+		DestinationAreaID:     "IDNP9",
+		DestinationPostalCode: "40115",
+		Items:                 `[{"name":"Shoes","quantity":1}]`,
+		AppliedRules:          `[1]`,
+		PriceDetail:           `{"price":9000}`,
+		ExpiresAt:             time.Unix(1790000000, 0),
+	}
+	signature := uc.sign(&quote)
+
+	// every stored field except the signature itself and the bookkeeping timestamps is signed
+	unsigned := map[string]bool{"Signature": true, "CreatedAt": true, "UpdatedAt": true}
+	fields := reflect.TypeOf(quote)
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		if unsigned[name] {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			tampered := quote
+			field := reflect.ValueOf(&tampered).Elem().Field(i)
+			switch value := field.Interface().(type) {
+			case string:
+				field.SetString(value + "x")
+			case int:
+				field.SetInt(int64(value + 1))
+			case time.Time:
+				field.Set(reflect.ValueOf(value.Add(time.Hour)))
+			default:
+				t.Fatalf("no tampering defined for %s of type %T", name, value)
+			}
+
+			if uc.sign(&tampered) == signature {
+				t.Errorf("changing %s kept the signature valid", name)
+			}
+		})
+	}
+
+	if uc.sign(&quote) != signature {
+		t.Error("sign() is not deterministic")
+	}
+	other := &QuoteUseCase{SigningKey: []byte("other-signing-key")}
+	if other.sign(&quote) == signature {
+		t.Error("sign() ignores the signing key")
+	}
+}
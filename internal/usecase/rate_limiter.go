@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"time"
+)
+
+// rateLimitScript records a request in a sorted set holding the requests of the last window and counts it against
+// the monthly quota, or refuses it without recording it anywhere when either is used up. A quota of 0 is unlimited.
+// It returns whether the request was allowed, the requests counted in the window, the milliseconds until the oldest
+// one leaves the window and the requests used this month.
+var rateLimitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local quota = tonumber(ARGV[5])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local used = 0
+if quota > 0 then
+	used = tonumber(redis.call('GET', KEYS[2]) or '0')
+end
+local allowed = 0
+if count < tonumber(ARGV[3]) and (quota <= 0 or used < quota) then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	if quota > 0 then
+		used = redis.call('INCR', KEYS[2])
+		if used == 1 then
+			redis.call('EXPIREAT', KEYS[2], ARGV[6])
+		end
+	end
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset, used}
+`)
+
+type RateLimitConfig struct {
+	Window       time.Duration  // Length of the sliding window
+	DefaultLimit int            // Requests allowed per window in route groups without their own limit
+	GroupLimits  map[string]int // Requests allowed per window, by route group
+	MonthlyQuota int            // Requests allowed per client and calendar month, 0 is unlimited
+}
+
+// RateLimitDecision describes the limit closest to being reached, it is reported in the RateLimit headers
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // Time until the limit frees up again
+	Policy    string        // Every limit applied to the request, in the RateLimit-Policy header format
+}
+
+// RateLimiter limits the requests of each API client with a sliding window per route group, and a monthly quota
+// across every group, both kept in Redis so the limits hold across instances. Limits set on a client override the
+// configured defaults. A client rate limit replaces the limit of every route group, each group still counting its
+// requests in its own window.
+type RateLimiter struct {
+	Redis    *redis.Client
+	Log      *logrus.Logger
+	Config   RateLimitConfig
+	Settings *Settings
+}
+
+func NewRateLimiter(redis *redis.Client, log *logrus.Logger, config RateLimitConfig, settings *Settings) *RateLimiter {
+	return &RateLimiter{
+		Redis:    redis,
+		Log:      log,
+		Config:   config,
+		Settings: settings,
+	}
+}
+
+// Allow counts a request of a client to a route group. The decision is nil when the request is not limited, either
+// because limiting is disabled or because Redis is unavailable, in which case requests are let through.
+func (rl *RateLimiter) Allow(ctx context.Context, client *entity.APIClient, group string) (*model.ServiceResponse, *RateLimitDecision) {
+	log := rl.Log.WithField("traceId", ctx.Value("traceId"))
+	if !rl.Settings.Load().RateLimitEnabled {
+		return model.Success(), nil
+	}
+
+	limit := rl.groupLimit(client, group)
+	quota := max(rl.monthlyQuota(client), 0)
+	window := rl.Config.Window
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := month.AddDate(0, 1, 0)
+
+	// the quota counter outlives the month by a day so late requests of the month do not start a fresh counter
+	keys := []string{fmt.Sprintf("ratelimit::%s::%s", client.ID, group), fmt.Sprintf("quota::%s::%s", client.ID, month.Format("2006-01"))}
+	res, err := rateLimitScript.Run(ctx, rl.Redis, keys, now.UnixMilli(), window.Milliseconds(), limit,
+		fmt.Sprintf("%d-%s", now.UnixNano(), uuid.NewString()), quota, nextMonth.Add(24*time.Hour).Unix()).Int64Slice()
+	if err != nil {
+		log.Warnf("Error checking rate limit of API client %s, request let through: %v", client.ID, err)
+		return model.Success(), nil
+	}
+
+	decision := &RateLimitDecision{
+		Allowed:   res[0] == 1,
+		Limit:     limit,
+		Remaining: max(limit-int(res[1]), 0),
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+		Policy:    fmt.Sprintf("%d;w=%d", limit, int(window.Seconds())),
+	}
+
+	quotaReported := false
+	if quota > 0 {
+		decision.Policy += fmt.Sprintf(", %d;w=%d", quota, int(nextMonth.Sub(month).Seconds()))
+		// the quota is reported when it refused the request or is closer to being reached than the window
+		quotaRemaining := max(quota-int(res[3]), 0)
+		if (!decision.Allowed && quotaRemaining == 0) || (decision.Allowed && quotaRemaining < decision.Remaining) {
+			quotaReported = true
+			decision.Limit = quota
+			decision.Remaining = quotaRemaining
+			decision.Reset = nextMonth.Sub(now)
+		}
+	}
+
+	if !decision.Allowed {
+		if quotaReported {
+			return model.TooManyRequests(fmt.Sprintf("Monthly quota of %d requests exceeded", quota)), decision
+		}
+		return model.TooManyRequests(fmt.Sprintf("Rate limit of %d requests per %s exceeded", limit, window)), decision
+	}
+	return model.Success(), decision
+}
+
+// groupLimit returns the requests allowed per window in a route group. The limit of a client applies to every group.
+func (rl *RateLimiter) groupLimit(client *entity.APIClient, group string) int {
+	if client.RateLimit > 0 {
+		return client.RateLimit
+	}
+	if limit, ok := rl.Config.GroupLimits[group]; ok && limit > 0 {
+		return limit
+	}
+	return rl.Config.DefaultLimit
+}
+
+func (rl *RateLimiter) monthlyQuota(client *entity.APIClient) int {
+	if client.MonthlyQuota != 0 {
+		return client.MonthlyQuota
+	}
+	return rl.Config.MonthlyQuota
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"shipping-gateway/internal/entity"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRateLimiter(t *testing.T, config RateLimitConfig) (*RateLimiter, *miniredis.Miniredis) {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	rds := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: rds.Addr(), MaxRetries: -1})
+	return NewRateLimiter(client, log, config, NewSettings(RuntimeSettings{RateLimitEnabled: true})), rds
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthSeconds := int(month.AddDate(0, 1, 0).Sub(month).Seconds())
+
+	type want struct {
+		status    int
+		allowed   bool
+		limit     int
+		remaining int
+		policy    string
+	}
+	tests := []struct {
+		name     string
+		config   RateLimitConfig
+		client   entity.APIClient
+		requests []want
+	}{
+		{
+			name:   "group limit",
+			config: RateLimitConfig{Window: time.Minute, DefaultLimit: 10, GroupLimits: map[string]int{"rates": 2}},
+			requests: []want{
+				{http.StatusOK, true, 2, 1, "2;w=60"},
+				{http.StatusOK, true, 2, 0, "2;w=60"},
+				{http.StatusTooManyRequests, false, 2, 0, "2;w=60"},
+			},
+		},
+		{
+			name:   "client limit overrides the group",
+			config: RateLimitConfig{Window: time.Minute, DefaultLimit: 10, GroupLimits: map[string]int{"rates": 2}},
+			client: entity.APIClient{RateLimit: 3},
+			requests: []want{
+				{http.StatusOK, true, 3, 2, "3;w=60"},
+				{http.StatusOK, true, 3, 1, "3;w=60"},
+				{http.StatusOK, true, 3, 0, "3;w=60"},
+				{http.StatusTooManyRequests, false, 3, 0, "3;w=60"},
+			},
+		},
+		{
+			name:   "window closer than the quota",
+			config: RateLimitConfig{Window: time.Minute, DefaultLimit: 2, MonthlyQuota: 5},
+			requests: []want{
+				{http.StatusOK, true, 2, 1, fmt.Sprintf("2;w=60, 5;w=%d", monthSeconds)},
+				{http.StatusOK, true, 2, 0, fmt.Sprintf("2;w=60, 5;w=%d", monthSeconds)},
+				{http.StatusTooManyRequests, false, 2, 0, fmt.Sprintf("2;w=60, 5;w=%d", monthSeconds)},
+			},
+		},
+		{
+			name:   "quota closer than the window",
+			config: RateLimitConfig{Window: time.Minute, DefaultLimit: 4, MonthlyQuota: 3},
+			requests: []want{
+				{http.StatusOK, true, 3, 2, fmt.Sprintf("4;w=60, 3;w=%d", monthSeconds)},
+				{http.StatusOK, true, 3, 1, fmt.Sprintf("4;w=60, 3;w=%d", monthSeconds)},
+				{http.StatusOK, true, 3, 0, fmt.Sprintf("4;w=60, 3;w=%d", monthSeconds)},
+				{http.StatusTooManyRequests, false, 3, 0, fmt.Sprintf("4;w=60, 3;w=%d", monthSeconds)},
+			},
+		},
+		{
+			name:   "unlimited client quota",
+			config: RateLimitConfig{Window: time.Minute, DefaultLimit: 1, MonthlyQuota: 3},
+			client: entity.APIClient{MonthlyQuota: -1},
+			requests: []want{
+				{http.StatusOK, true, 1, 0, "1;w=60"},
+				{http.StatusTooManyRequests, false, 1, 0, "1;w=60"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, _ := newTestRateLimiter(t, tt.config)
+			client := tt.client
+			client.ID = "client-1"
+			for i, w := range tt.requests {
+				resp, decision := limiter.Allow(context.Background(), &client, "rates")
+				if decision == nil {
+					t.Fatalf("request %d: decision is nil", i)
+				}
+				got := want{resp.StatusCode, decision.Allowed, decision.Limit, decision.Remaining, decision.Policy}
+				if got != w {
+					t.Fatalf("request %d = %+v, want %+v", i, got, w)
+				}
+				if decision.Reset <= 0 {
+					t.Fatalf("request %d: reset = %v, want positive", i, decision.Reset)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterAllowQuotaReset(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t, RateLimitConfig{Window: time.Minute, DefaultLimit: 10, MonthlyQuota: 1})
+	client := &entity.APIClient{ID: "client-1"}
+
+	limiter.Allow(context.Background(), client, "rates")
+	resp, decision := limiter.Allow(context.Background(), client, "rates")
+	if decision == nil || decision.Allowed {
+		t.Fatalf("request over the quota = %+v, want refused", decision)
+	}
+	if !strings.Contains(resp.Message, "Monthly quota") {
+		t.Errorf("message = %q, want the monthly quota named", resp.Message)
+	}
+	// the quota frees up at the start of next month, after the window would
+	if decision.Reset <= time.Minute {
+		t.Errorf("reset = %v, want the time until next month", decision.Reset)
+	}
+}
+
+func TestRateLimiterAllowLetsThrough(t *testing.T) {
+	limiter, rds := newTestRateLimiter(t, RateLimitConfig{Window: time.Minute, DefaultLimit: 1})
+	client := &entity.APIClient{ID: "client-1"}
+
+	rds.SetError("connection refused")
+	for i := 0; i < 3; i++ {
+		if resp, decision := limiter.Allow(context.Background(), client, "rates"); resp.StatusCode != http.StatusOK || decision != nil {
+			t.Fatalf("request %d with redis down = %d, %+v, want let through", i, resp.StatusCode, decision)
+		}
+	}
+
+	rds.SetError("")
+	limiter.Settings.Store(RuntimeSettings{RateLimitEnabled: false})
+	for i := 0; i < 3; i++ {
+		if resp, decision := limiter.Allow(context.Background(), client, "rates"); resp.StatusCode != http.StatusOK || decision != nil {
+			t.Fatalf("request %d with limiting disabled = %d, %+v, want let through", i, resp.StatusCode, decision)
+		}
+	}
+}
+
+func TestRateLimiterRefusalConsumesNothing(t *testing.T) {
+	month := time.Now().UTC().Format("2006-01")
+
+	t.Run("window refusal keeps the quota", func(t *testing.T) {
+		limiter, rds := newTestRateLimiter(t, RateLimitConfig{Window: time.Minute, DefaultLimit: 1, MonthlyQuota: 5})
+		client := &entity.APIClient{ID: "client-1"}
+		for i := 0; i < 3; i++ {
+			limiter.Allow(context.Background(), client, "rates")
+		}
+		if used, _ := rds.Get("quota::client-1::" + month); used != "1" {
+			t.Errorf("quota used = %s, want only the allowed request counted", used)
+		}
+	})
+
+	t.Run("quota refusal keeps the window", func(t *testing.T) {
+		limiter, rds := newTestRateLimiter(t, RateLimitConfig{Window: time.Minute, DefaultLimit: 10, MonthlyQuota: 1})
+		client := &entity.APIClient{ID: "client-1"}
+		for i := 0; i < 3; i++ {
+			limiter.Allow(context.Background(), client, "rates")
+		}
+		if members, _ := rds.ZMembers("ratelimit::client-1::rates"); len(members) != 1 {
+			t.Errorf("window slots = %d, want only the allowed request recorded", len(members))
+		}
+	})
+
+	t.Run("client limit applies to each group separately", func(t *testing.T) {
+		limiter, _ := newTestRateLimiter(t, RateLimitConfig{Window: time.Minute, DefaultLimit: 10, GroupLimits: map[string]int{"tracking": 5}})
+		client := &entity.APIClient{ID: "client-1", RateLimit: 1}
+		for _, group := range []string{"rates", "tracking"} {
+			if resp, decision := limiter.Allow(context.Background(), client, group); resp.StatusCode != http.StatusOK || decision.Limit != 1 {
+				t.Errorf("first request to %s = %d, %+v, want allowed with the client limit", group, resp.StatusCode, decision)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"math"
+	"regexp"
+	"shipping-gateway/internal/model"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RecommendWeights sets how much price, speed and on-time rate contribute to a price score
+type RecommendWeights struct {
+	Price  float64
+	Speed  float64
+	OnTime float64
+}
+
+var etdNumberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// etdHours converts an ETD such as "1 - 2 days" or "3 - 6 hours" to the upper bound in hours.
+// It returns -1 when the ETD cannot be parsed.
+func etdHours(etd string) float64 {
+	numbers := etdNumberPattern.FindAllString(etd, -1)
+	if len(numbers) == 0 {
+		return -1
+	}
+
+	upper := 0.0
+	for _, number := range numbers {
+		value, err := strconv.ParseFloat(number, 64)
+		if err == nil && value > upper {
+			upper = value
+		}
+	}
+
+	lower := strings.ToLower(etd)
+	switch {
+	case strings.Contains(lower, "hour") || strings.Contains(lower, "jam"):
+		return upper
+	case strings.Contains(lower, "week") || strings.Contains(lower, "minggu"):
+		return upper * 24 * 7
+	default:
+		return upper * 24
+	}
+}
+
+// filterPrices drops the prices that do not satisfy the request filter
+func filterPrices(prices []model.CourierPrice, filter model.RateFilter) []model.CourierPrice {
+	filtered := make([]model.CourierPrice, 0, len(prices))
+	for _, price := range prices {
+		if len(filter.ServiceTypes) > 0 && !slices.ContainsFunc(filter.ServiceTypes, func(serviceType string) bool {
+			return strings.EqualFold(serviceType, price.ServiceType)
+		}) {
+			continue
+		}
+		if filter.RequireCOD && !price.Capabilities.CashOnDelivery {
+			continue
+		}
+		if filter.RequireInsurance && !price.Capabilities.Insurance {
+			continue
+		}
+		if filter.RequireInstantWaybill && !price.Capabilities.InstantWaybill {
+			continue
+		}
+		if filter.MaxPrice > 0 && price.Breakdown.Total > filter.MaxPrice {
+			continue
+		}
+		filtered = append(filtered, price)
+	}
+	return filtered
+}
+
+// sortPrices orders the prices in place, keeping provider order for ties
+func sortPrices(prices []model.CourierPrice, sortBy model.RateSortBy) {
+	switch sortBy {
+	case model.RateSortByPrice:
+		sort.SliceStable(prices, func(i, j int) bool {
+			return prices[i].Breakdown.Total < prices[j].Breakdown.Total
+		})
+	case model.RateSortByETD:
+		sort.SliceStable(prices, func(i, j int) bool {
+			return compareETD(prices[i].ETD, prices[j].ETD)
+		})
+	}
+}
+
+// compareETD reports whether ETD a is faster than b, unknown ETDs sort last
+func compareETD(a, b string) bool {
+	hoursA, hoursB := etdHours(a), etdHours(b)
+	if hoursA < 0 {
+		return false
+	}
+	if hoursB < 0 {
+		return true
+	}
+	return hoursA < hoursB
+}
+
+// scorePrices scores each price between 0 and 1 from its relative price, relative speed and historical
+// on-time rate, then flags the best scoring price as recommended.
+func scorePrices(prices []model.CourierPrice, weights RecommendWeights, onTimeRate func(model.CourierPrice) float64) {
+	if len(prices) == 0 {
+		return
+	}
+
+	minPrice, maxPrice := math.MaxFloat64, 0.0
+	minHours, maxHours := math.MaxFloat64, 0.0
+	for _, price := range prices {
+		minPrice = math.Min(minPrice, float64(price.Breakdown.Total))
+		maxPrice = math.Max(maxPrice, float64(price.Breakdown.Total))
+		if hours := etdHours(price.ETD); hours >= 0 {
+			minHours = math.Min(minHours, hours)
+			maxHours = math.Max(maxHours, hours)
+		}
+	}
+
+	totalWeight := weights.Price + weights.Speed + weights.OnTime
+	if totalWeight <= 0 {
+		return
+	}
+
+	best := 0
+	for i := range prices {
+		priceScore := normalizeInverse(float64(prices[i].Breakdown.Total), minPrice, maxPrice)
+
+		speedScore := 0.0
+		if hours := etdHours(prices[i].ETD); hours >= 0 {
+			speedScore = normalizeInverse(hours, minHours, maxHours)
+		}
+
+		score := (weights.Price*priceScore + weights.Speed*speedScore + weights.OnTime*onTimeRate(prices[i])) / totalWeight
+		prices[i].Score = math.Round(score*10000) / 10000
+		prices[i].Recommended = false
+		if prices[i].Score > prices[best].Score {
+			best = i
+		}
+	}
+	prices[best].Recommended = true
+}
+
+// normalizeInverse maps value to 1 at min and 0 at max
+func normalizeInverse(value, min, max float64) float64 {
+	if max <= min {
+		return 1
+	}
+	return (max - value) / (max - min)
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"shipping-gateway/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestEtdHours(t *testing.T) {
+	tests := []struct {
+		etd  string
+		want float64
+	}{
+		{"1 - 2 days", 48},
+		{"2", 48},
+		{"1 - 3 hours", 3},
+		{"6 jam", 6},
+		{"1 - 2 weeks", 336},
+		{"2 minggu", 336},
+		{"0.5 - 1.5 days", 36},
+		{"", -1},
+		{"same day", -1},
+	}
+	for _, tt := range tests {
+		if got := etdHours(tt.etd); got != tt.want {
+			t.Errorf("etdHours(%q) = %v, want %v", tt.etd, got, tt.want)
+		}
+	}
+}
+
+func testRankingPrices() []model.CourierPrice {
+	return []model.CourierPrice{
+		{CourierCode: "jne", Breakdown: model.PriceBreakdown{Total: 10000}, ETD: "1 - 2 days"},
+		{CourierCode: "gojek", Breakdown: model.PriceBreakdown{Total: 20000}, ETD: "1 - 3 hours"},
+		{CourierCode: "pos", Breakdown: model.PriceBreakdown{Total: 15000}, ETD: ""},
+		{CourierCode: "tiki", Breakdown: model.PriceBreakdown{Total: 10000}, ETD: "3 days"},
+	}
+}
+
+func courierCodes(prices []model.CourierPrice) string {
+	codes := make([]string, 0, len(prices))
+	for _, price := range prices {
+		codes = append(codes, price.CourierCode)
+	}
+	return strings.Join(codes, ",")
+}
+
+func TestSortPrices(t *testing.T) {
+	tests := []struct {
+		sortBy model.RateSortBy
+		want   string
+	}{
+		{model.RateSortByPrice, "jne,tiki,pos,gojek"},
+		{model.RateSortByETD, "gojek,jne,tiki,pos"},
+		{"", "jne,gojek,pos,tiki"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			prices := testRankingPrices()
+			sortPrices(prices, tt.sortBy)
+			if got := courierCodes(prices); got != tt.want {
+				t.Errorf("sortPrices(%q) = %s, want %s", tt.sortBy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScorePrices(t *testing.T) {
+	onTimeRate := func(price model.CourierPrice) float64 {
+		if price.CourierCode == "pos" {
+			return 1
+		}
+		return 0.5
+	}
+
+	tests := []struct {
+		name            string
+		weights         RecommendWeights
+		wantScores      []float64
+		wantRecommended string
+	}{
+		{"price only, first of ties wins", RecommendWeights{Price: 1}, []float64{1, 0, 0.5, 1}, "jne"},
+		{"speed only, unknown etd scores 0", RecommendWeights{Speed: 1}, []float64{0.3478, 1, 0, 0}, "gojek"},
+		{"on-time only", RecommendWeights{OnTime: 1}, []float64{0.5, 0.5, 1, 0.5}, "pos"},
+		{"weighted", RecommendWeights{Price: 2, Speed: 1, OnTime: 1}, []float64{0.712, 0.375, 0.5, 0.625}, "jne"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := testRankingPrices()
+			scorePrices(prices, tt.weights, onTimeRate)
+
+			recommended := make([]string, 0, 1)
+			for i, price := range prices {
+				if price.Score != tt.wantScores[i] {
+					t.Errorf("%s score = %v, want %v", price.CourierCode, price.Score, tt.wantScores[i])
+				}
+				if price.Recommended {
+					recommended = append(recommended, price.CourierCode)
+				}
+			}
+			if got := strings.Join(recommended, ","); got != tt.wantRecommended {
+				t.Errorf("recommended = %s, want %s", got, tt.wantRecommended)
+			}
+		})
+	}
+}
+
+func TestScorePricesWithoutWeights(t *testing.T) {
+	prices := testRankingPrices()
+	scorePrices(prices, RecommendWeights{}, func(model.CourierPrice) float64 { return 1 })
+	for _, price := range prices {
+		if price.Score != 0 || price.Recommended {
+			t.Errorf("%s = %+v, want no score without weights", price.CourierCode, price)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+)
+
+// regionCachePattern matches every cached region list
+const regionCachePattern = "regions::*"
+
+type RegionUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	Redis           *redis.Client
+	AreaRepo        *repository.AreaRepository
+	ProvinceRepo    *repository.ProvinceRepository
+	CityRepo        *repository.CityRepository
+	DistrictRepo    *repository.DistrictRepository
+	SubdistrictRepo *repository.SubdistrictRepository
+	Settings        *Settings
+}
+
+func NewRegionUseCase(db *gorm.DB, log *logrus.Logger, redis *redis.Client, areaRepo *repository.AreaRepository,
+	provinceRepo *repository.ProvinceRepository, cityRepo *repository.CityRepository, districtRepo *repository.DistrictRepository,
+	subdistrictRepo *repository.SubdistrictRepository, settings *Settings) *RegionUseCase {
+	return &RegionUseCase{
+		DB:              db,
+		Log:             log,
+		Redis:           redis,
+		AreaRepo:        areaRepo,
+		ProvinceRepo:    provinceRepo,
+		CityRepo:        cityRepo,
+		DistrictRepo:    districtRepo,
+		SubdistrictRepo: subdistrictRepo,
+		Settings:        settings,
+	}
+}
+
+func (uc *RegionUseCase) ListProvinces(ctx context.Context) (*model.ServiceResponse, []model.ProvinceResponse) {
+	return cachedRegions(ctx, uc, "regions::provinces", func() ([]model.ProvinceResponse, error) {
+		provinces, err := uc.ProvinceRepo.FindAll(uc.DB)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := make([]model.ProvinceResponse, 0, len(provinces))
+		for _, province := range provinces {
+			resp = append(resp, model.ProvinceResponse{ID: province.ID, Name: province.Name})
+		}
+		return resp, nil
+	})
+}
+
+func (uc *RegionUseCase) ListCities(ctx context.Context, provinceID uint) (*model.ServiceResponse, []model.CityResponse) {
+	return cachedRegions(ctx, uc, fmt.Sprintf("regions::cities::%d", provinceID), func() ([]model.CityResponse, error) {
+		if _, err := uc.ProvinceRepo.FindByID(uc.DB, provinceID); err != nil {
+			return nil, err
+		}
+		cities, err := uc.CityRepo.FindByProvinceID(uc.DB, provinceID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := make([]model.CityResponse, 0, len(cities))
+		for _, city := range cities {
+			resp = append(resp, model.CityResponse{ID: city.ID, ProvinceID: city.ProvinceID, Type: city.Type, Name: city.Name})
+		}
+		return resp, nil
+	})
+}
+
+func (uc *RegionUseCase) ListDistricts(ctx context.Context, cityID uint) (*model.ServiceResponse, []model.DistrictResponse) {
+	return cachedRegions(ctx, uc, fmt.Sprintf("regions::districts::%d", cityID), func() ([]model.DistrictResponse, error) {
+		if _, err := uc.CityRepo.FindByID(uc.DB, cityID); err != nil {
+			return nil, err
+		}
+		districts, err := uc.DistrictRepo.FindByCityID(uc.DB, cityID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := make([]model.DistrictResponse, 0, len(districts))
+		for _, district := range districts {
+			resp = append(resp, model.DistrictResponse{ID: district.ID, CityID: district.CityID, Name: district.Name})
+		}
+		return resp, nil
+	})
+}
+
+// ListSubdistricts returns the subdistricts of a district with the provider area each one is mapped to
+func (uc *RegionUseCase) ListSubdistricts(ctx context.Context, districtID uint) (*model.ServiceResponse, []model.SubdistrictResponse) {
+	return cachedRegions(ctx, uc, SubdistrictCacheKey(districtID), func() ([]model.SubdistrictResponse, error) {
+		if _, err := uc.DistrictRepo.FindByID(uc.DB, districtID); err != nil {
+			return nil, err
+		}
+		subdistricts, err := uc.SubdistrictRepo.FindByDistrictID(uc.DB, districtID)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]uint, 0, len(subdistricts))
+		for _, subdistrict := range subdistricts {
+			ids = append(ids, subdistrict.ID)
+		}
+		areas, err := uc.AreaRepo.FindBySubdistrictIDs(uc.DB, "biteship", ids)
+		if err != nil {
+			return nil, err
+		}
+		areaIDs := make(map[uint]string, len(areas))
+		for _, area := range areas {
+			areaIDs[area.OriginalSubdistrictID] = area.ExternalID
+		}
+
+		resp := make([]model.SubdistrictResponse, 0, len(subdistricts))
+		for _, subdistrict := range subdistricts {
+			resp = append(resp, model.SubdistrictResponse{
+				ID:         subdistrict.ID,
+				DistrictID: subdistrict.DistrictID,
+				Name:       subdistrict.Name,
+				PostalCode: subdistrict.PostalCode,
+				AreaID:     areaIDs[subdistrict.ID],
+			})
+		}
+		return resp, nil
+	})
+}
+
+// SubdistrictCacheKey is the key of the cached subdistricts of a district. It must be dropped whenever the
+// area mapping of one of those subdistricts changes.
+func SubdistrictCacheKey(districtID uint) string {
+	return fmt.Sprintf("regions::subdistricts::%d", districtID)
+}
+
+// ClearRegionCache drops every cached region list, used after the master data is imported again
+func ClearRegionCache(ctx context.Context, rds *redis.Client) error {
+	iter := rds.Scan(ctx, 0, regionCachePattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := rds.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// cachedRegions returns the region list cached under key, loading and caching it on a miss.
+// A missing parent region is reported as not found.
+func cachedRegions[T any](ctx context.Context, uc *RegionUseCase, key string, load func() ([]T, error)) (*model.ServiceResponse, []T) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	var regions []T
+	cached, err := uc.Redis.Get(ctx, key).Result()
+	if err == nil {
+		if err = json.Unmarshal([]byte(cached), &regions); err == nil {
+			return model.Success(), regions
+		}
+		log.Errorf("Error unmarshalling cached regions %s: %v", key, err)
+	} else if !errors.Is(err, redis.Nil) {
+		log.Errorf("Error getting regions from Redis: %v", err)
+	}
+
+	regions, err = load()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.NotFound("region not found"), nil
+	}
+	if err != nil {
+		log.Errorf("Error loading regions %s: %v", key, err)
+		return model.DefaultError("failed to load regions", nil), nil
+	}
+
+	if bRegions, err := json.Marshal(regions); err != nil {
+		log.Errorf("Error marshalling regions %s: %v", key, err)
+	} else if err = uc.Redis.Set(ctx, key, bRegions, uc.Settings.Load().RegionCacheTTL).Err(); err != nil {
+		log.Errorf("Error setting regions in Redis: %v", err)
+	}
+
+	return model.Success(), regions
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"reflect"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"testing"
+	"time"
+)
+
+func newTestRegionUseCase(t *testing.T, db *gorm.DB) (*RegionUseCase, *miniredis.Miniredis) {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	rds := miniredis.RunT(t)
+	uc := NewRegionUseCase(db, log, redis.NewClient(&redis.Options{Addr: rds.Addr()}), repository.NewAreaRepository(),
+		repository.NewProvinceRepository(), repository.NewCityRepository(), repository.NewDistrictRepository(),
+		repository.NewSubdistrictRepository(), NewSettings(RuntimeSettings{RegionCacheTTL: time.Hour}))
+	return uc, rds
+}
+
+func TestCachedRegions(t *testing.T) {
+	provinces := []model.ProvinceResponse{{ID: 31, Name: "DKI Jakarta"}}
+
+	tests := []struct {
+		name       string
+		cached     string
+		loadErr    error
+		wantStatus int
+		wantLoads  int
+		wantData   []model.ProvinceResponse
+		wantCached bool
+	}{
+		{"cache hit", `[{"id":32,"name":"Jawa Barat"}]`, nil, http.StatusOK, 0, []model.ProvinceResponse{{ID: 32, Name: "Jawa Barat"}}, true},
+		{"cache miss", "", nil, http.StatusOK, 1, provinces, true},
+		{"corrupt cache reloaded", "not json", nil, http.StatusOK, 1, provinces, true},
+		{"missing parent", "", gorm.ErrRecordNotFound, http.StatusNotFound, 1, nil, false},
+		{"load error", "", errors.New("connection refused"), http.StatusInternalServerError, 1, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, rds := newTestRegionUseCase(t, nil)
+			if tt.cached != "" {
+				_ = rds.Set("regions::provinces", tt.cached)
+			}
+
+			loads := 0
+			ucResp, got := cachedRegions(context.Background(), uc, "regions::provinces", func() ([]model.ProvinceResponse, error) {
+				loads++
+				return provinces, tt.loadErr
+			})
+
+			if ucResp.StatusCode != tt.wantStatus || loads != tt.wantLoads || !reflect.DeepEqual(got, tt.wantData) {
+				t.Errorf("cachedRegions() = %d %+v after %d loads, want %d %+v after %d loads",
+					ucResp.StatusCode, got, loads, tt.wantStatus, tt.wantData, tt.wantLoads)
+			}
+			if rds.Exists("regions::provinces") != tt.wantCached {
+				t.Errorf("cached = %v, want %v", rds.Exists("regions::provinces"), tt.wantCached)
+			}
+			if tt.wantCached && tt.wantLoads > 0 && rds.TTL("regions::provinces") != time.Hour {
+				t.Errorf("TTL = %v, want the region cache TTL", rds.TTL("regions::provinces"))
+			}
+		})
+	}
+}
+
+func TestClearRegionCache(t *testing.T) {
+	uc, rds := newTestRegionUseCase(t, nil)
+	for _, key := range []string{"regions::provinces", "regions::cities::31", SubdistrictCacheKey(3174021), "area::1::12920"} {
+		_ = rds.Set(key, "[]")
+	}
+
+	if err := ClearRegionCache(context.Background(), uc.Redis); err != nil {
+		t.Fatalf("ClearRegionCache() error = %v", err)
+	}
+	if keys := rds.Keys(); !reflect.DeepEqual(keys, []string{"area::1::12920"}) {
+		t.Errorf("keys left = %v, want only the area mapping", keys)
+	}
+}
+
+func TestRegionUseCaseListSubdistricts(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery("FROM `districts`").WithArgs(3174021, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "city_id", "name"}).AddRow(3174021, 3174, "Setiabudi"))
+	mock.ExpectQuery("FROM `subdistricts`").WithArgs(3174021).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "district_id", "name", "postal_code"}).
+			AddRow(3174021001, 3174021, "Karet", "12920").
+			AddRow(3174021002, 3174021, "Kuningan", "12940"))
+	mock.ExpectQuery("FROM `areas`").WithArgs("biteship", 3174021001, 3174021002).
+		WillReturnRows(sqlmock.NewRows([]string{"original_subdistrict_id", "external_id"}).AddRow(3174021001, "area-karet"))
+
+	uc, rds := newTestRegionUseCase(t, db)
+	ucResp, got := uc.ListSubdistricts(context.Background(), 3174021)
+
+	want := []model.SubdistrictResponse{
+		{ID: 3174021001, DistrictID: 3174021, Name: "Karet", PostalCode: "12920", AreaID: "area-karet"},
+		{ID: 3174021002, DistrictID: 3174021, Name: "Kuningan", PostalCode: "12940"},
+	}
+	if ucResp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSubdistricts() = %d %+v, want %+v", ucResp.StatusCode, got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if !rds.Exists(SubdistrictCacheKey(3174021)) {
+		t.Error("subdistricts were not cached")
+	}
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RuntimeSettings are the settings that can be changed while the gateway runs, by editing the config file or
+// sending SIGHUP. Everything else is read once at startup.
+type RuntimeSettings struct {
+	RateCacheTTL            time.Duration // How long provider rates are cached
+	SendChargeableWeight    bool          // Send chargeable instead of actual item weight to the provider
+	TrackingRefetchInterval time.Duration // How long a tracking is served from cache before it is fetched again
+	RegionCacheTTL          time.Duration // How long region lists are cached
+	RateLimitEnabled        bool          // Whether API clients are rate limited
+	AreaSearchEnabled       bool          // Whether areas are matched against the local search index before the provider
+}
+
+// Settings holds the current runtime settings. They are replaced as a whole, so a request never sees a mix of
+// old and new values.
+type Settings struct {
+	current atomic.Pointer[RuntimeSettings]
+}
+
+func NewSettings(initial RuntimeSettings) *Settings {
+	s := &Settings{}
+	s.Store(initial)
+	return s
+}
+
+// Load returns the current runtime settings
+func (s *Settings) Load() RuntimeSettings {
+	return *s.current.Load()
+}
+
+// Store replaces the runtime settings
+func (s *Settings) Store(settings RuntimeSettings) {
+	s.current.Store(&settings)
+}
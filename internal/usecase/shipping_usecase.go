@@ -2,43 +2,132 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"math"
 	"shipping-gateway/external/biteship"
 	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 type ShippingUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	AreaUseCase    *AreaUseCase
-	BiteshipClient *biteship.Client
-	Redis          *redis.Client
+	DB            *gorm.DB
+	Log           *logrus.Logger
+	Validate      *validator.Validate
+	AreaUseCase   *AreaUseCase
+	AddressUC     *AddressUseCase
+	Couriers      *CourierRegistry
+	PricingRuleUC *PricingRuleUseCase
+	QuoteUC       *QuoteUseCase
+	PackingUC     *PackingUseCase
+	ServiceRepo   *repository.CourierServiceRepository
+	Tenants       *TenantRegistry
+	Redis         *redis.Client
+	Config        ShippingConfig
+	Settings      *Settings
+
+	// rateGroup collapses concurrent identical rate requests into one provider call
+	rateGroup singleflight.Group
+}
+
+// ShippingConfig holds the tunable settings used when quoting courier rates
+type ShippingConfig struct {
+	VolumetricDivisor int            // Default volumetric divisor in cm³ per kg
+	CourierDivisors   map[string]int // Volumetric divisor overrides keyed by courier code
+	RecommendWeights  RecommendWeights
+	DefaultOnTimeRate float64      // On-time rate assumed for services without history
+	InsuranceFees     FeeSchedules // Insurance premium schedules
+	CODFees           FeeSchedules // Cash on delivery fee schedules
+	BatchMaxLegs      int          // Maximum number of legs in a batch rate request
+	BatchConcurrency  int          // Number of batch legs quoted concurrently
+	InstantCouriers   []string     // Couriers that require coordinates, e.g. gojek or grab
+}
+
+// IsInstantCourier reports whether the courier requires coordinates to be quoted
+func (c ShippingConfig) IsInstantCourier(courierCode string) bool {
+	return slices.ContainsFunc(c.InstantCouriers, func(code string) bool {
+		return strings.EqualFold(code, courierCode)
+	})
+}
+
+// DivisorFor returns the volumetric divisor used by the given courier
+func (c ShippingConfig) DivisorFor(courierCode string) int {
+	if divisor, ok := c.CourierDivisors[strings.ToLower(courierCode)]; ok && divisor > 0 {
+		return divisor
+	}
+	return c.VolumetricDivisor
 }
 
 func NewShippingUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate,
-	areaUseCase *AreaUseCase, bc *biteship.Client, redis *redis.Client) *ShippingUseCase {
+	areaUseCase *AreaUseCase, addressUC *AddressUseCase, couriers *CourierRegistry, pricingRuleUC *PricingRuleUseCase, quoteUC *QuoteUseCase, packingUC *PackingUseCase,
+	serviceRepo *repository.CourierServiceRepository, tenants *TenantRegistry, redis *redis.Client, config ShippingConfig, settings *Settings) *ShippingUseCase {
 	return &ShippingUseCase{
-		AreaUseCase:    areaUseCase,
-		BiteshipClient: bc,
-		DB:             db,
-		Log:            log,
-		Validate:       validate,
-		Redis:          redis,
+		AreaUseCase:   areaUseCase,
+		AddressUC:     addressUC,
+		Couriers:      couriers,
+		PricingRuleUC: pricingRuleUC,
+		QuoteUC:       quoteUC,
+		PackingUC:     packingUC,
+		ServiceRepo:   serviceRepo,
+		Tenants:       tenants,
+		DB:            db,
+		Log:           log,
+		Validate:      validate,
+		Redis:         redis,
+		Config:        config,
+		Settings:      settings,
 	}
 }
 
+// rateResult carries the outcome of a provider rate call through singleflight
+type rateResult struct {
+	resp    *model.CourierRateResponse
+	errResp *biteship.ErrorResponse
+}
+
 func (uc *ShippingUseCase) GetCourierRates(ctx context.Context, req *model.CourierRateRequest) (*model.ServiceResponse, *model.CourierRateResponse) {
+	return uc.getCourierRates(ctx, req, uc.AreaUseCase.FindArea)
+}
+
+func (uc *ShippingUseCase) getCourierRates(ctx context.Context, req *model.CourierRateRequest, findArea areaFinder) (*model.ServiceResponse, *model.CourierRateResponse) {
 	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
 	log.Infof("GetCourierRates request: %+v", req)
 
+	tenant, err := uc.Tenants.Resolve(ctx)
+	if err != nil {
+		return tenantErrorResponse(log, err), nil
+	}
+	if merchant := tenant.Merchant; merchant != nil {
+		if req.CourierCode == "" {
+			req.CourierCode = merchant.DefaultCouriers
+		}
+		if req.OriginSubdistrictID == "" && req.OriginPostalCode == "" && req.OriginQuery == "" && req.OriginAddress == "" {
+			req.OriginPostalCode = merchant.DefaultOriginPostalCode
+		}
+	}
+	if req.CourierCode == "" {
+		return model.BadRequest("Courier code is required", nil), nil
+	}
+
+	// Free-text addresses only fill the location fields the caller left empty
+	uc.AddressUC.ApplyAddress(ctx, req.OriginAddress, &req.OriginSubdistrictID, &req.OriginPostalCode, &req.OriginQuery)
+	uc.AddressUC.ApplyAddress(ctx, req.DestinationAddress, &req.DestinationSubdistrictID, &req.DestinationPostalCode, &req.DestinationQuery)
+
 	var bsReq biteship.RateRequest
 
-	originArea, err := uc.AreaUseCase.FindArea(ctx, req.OriginSubdistrictID, req.OriginPostalCode, req.OriginQuery)
+	originArea, err := findArea(ctx, req.OriginSubdistrictID, req.OriginPostalCode, req.OriginQuery)
 	if err != nil || originArea == nil || originArea.ExternalID == "" {
 		if req.OriginPostalCode == "" {
 			log.Errorf("Origin postal code is required when origin area is not found: %+v", req)
@@ -50,7 +139,7 @@ func (uc *ShippingUseCase) GetCourierRates(ctx context.Context, req *model.Couri
 		bsReq.OriginAreaID = originArea.ExternalID
 	}
 
-	destinationArea, err := uc.AreaUseCase.FindArea(ctx, req.DestinationSubdistrictID, req.DestinationPostalCode, req.DestinationQuery)
+	destinationArea, err := findArea(ctx, req.DestinationSubdistrictID, req.DestinationPostalCode, req.DestinationQuery)
 	if err != nil || destinationArea == nil || destinationArea.ExternalID == "" {
 		if req.DestinationPostalCode == "" {
 			log.Errorf("Destination postal code is required when destination area is not found: %+v", req)
@@ -62,31 +151,304 @@ func (uc *ShippingUseCase) GetCourierRates(ctx context.Context, req *model.Couri
 		bsReq.DestinationAreaID = destinationArea.ExternalID
 	}
 
-	bsReq.Couriers = req.CourierCode
-	bsReq.Items = make([]biteship.Item, 0, len(req.Items))
-	for _, item := range req.Items {
-		bsReq.Items = append(bsReq.Items, biteship.Item{
+	// Packed items are shipped as one package per box instead of one package per item
+	items := req.Items
+	var packing *model.PackingResult
+	if req.Packing != nil {
+		ucResp, result := uc.PackingUC.Pack(ctx, *req.Packing, req.Items)
+		if result == nil {
+			return ucResp, nil
+		}
+		packing = result
+		items = packing.ToItemRequests()
+	}
+
+	// Instant couriers can only be quoted with coordinates, which either come from the request or
+	// from the resolved areas
+	origin := coordinateOf(req.OriginLatitude, req.OriginLongitude, originArea)
+	destination := coordinateOf(req.DestinationLatitude, req.DestinationLongitude, destinationArea)
+	hasCoordinates := origin != nil && destination != nil
+
+	courierCodes := req.CourierCode
+	if req.IncludeInstant && hasCoordinates {
+		courierCodes += "," + strings.Join(uc.Config.InstantCouriers, ",")
+	}
+	courierCodes = uc.Couriers.CanonicalList(courierCodes)
+
+	// Couriers sharing a volumetric divisor are quoted together. Unless chargeable weight is sent upstream
+	// the item weights are the same for every courier, so a single provider call covers them all.
+	groups := uc.groupCouriers(courierCodes, hasCoordinates)
+
+	var resp *model.CourierRateResponse
+	cacheStatus := model.RateCacheHit
+
+	for _, group := range groups {
+		groupReq := bsReq
+		providerCodes := make([]string, 0, len(group.couriers))
+		for _, code := range group.couriers {
+			providerCodes = append(providerCodes, uc.Couriers.ProviderCode(ProviderBiteship, code))
+		}
+		groupReq.Couriers = strings.Join(providerCodes, ",")
+		groupReq.Items = uc.toBiteshipItems(items, group.divisor)
+		if group.instant {
+			groupReq.OriginLatitude, groupReq.OriginLongitude = origin.Latitude, origin.Longitude
+			groupReq.DestinationLatitude, groupReq.DestinationLongitude = destination.Latitude, destination.Longitude
+		}
+
+		groupResp, groupStatus, errResp := uc.fetchCourierRates(ctx, tenant.Biteship, groupReq, req.BypassCache)
+		if errResp != nil {
+			if errResp.IsEmptyData() {
+				continue
+			}
+			log.Errorf("Error getting courier rates from Biteship: %v", errResp)
+			return model.DefaultError("Failed to get courier rates", nil), nil
+		}
+
+		if groupStatus != model.RateCacheHit {
+			cacheStatus = groupStatus
+		}
+		for i := range groupResp.Data.Prices {
+			groupResp.Data.Prices[i].CourierCode = uc.Couriers.FromProvider(ProviderBiteship, groupResp.Data.Prices[i].CourierCode)
+		}
+		if resp == nil {
+			resp = groupResp
+			continue
+		}
+		resp.Data.Prices = append(resp.Data.Prices, groupResp.Data.Prices...)
+	}
+
+	if resp == nil {
+		log.Infof("No courier rates found for request: %+v", req)
+		return model.NotFound("No courier rates found"), nil
+	}
+
+	for i := range resp.Data.Prices {
+		divisor := uc.Config.DivisorFor(resp.Data.Prices[i].CourierCode)
+		resp.Data.Prices[i].Weight = model.NewWeightBreakdown(items, divisor)
+	}
+	resp.Data.Packing = packing
+	if hasCoordinates {
+		distance := math.Round(distanceKm(*origin, *destination)*100) / 100
+		resp.Data.DistanceKm = &distance
+	}
+
+	pricingCtx := PricingContext{
+		DestinationProvince: resp.Data.Destination.Province,
+		CartValue:           req.CartValue(),
+	}
+	if err := uc.PricingRuleUC.ApplyRules(ctx, pricingCtx, resp.Data.Prices); err != nil {
+		return model.DefaultError("Failed to apply pricing rules", nil), nil
+	}
+
+	applyFees(resp.Data.Prices, req, uc.Config.InsuranceFees, uc.Config.CODFees)
+
+	// Services that cannot insure or collect payment are useless for a quote that asks for it
+	filter := req.Filter
+	filter.RequireInsurance = filter.RequireInsurance || req.Insurance
+	filter.RequireCOD = filter.RequireCOD || req.COD
+	resp.Data.Prices = filterPrices(resp.Data.Prices, filter)
+	if len(resp.Data.Prices) == 0 {
+		log.Infof("No courier rates left after filtering for request: %+v", req)
+		return model.NotFound("No courier rates match the given filter"), nil
+	}
+	scorePrices(resp.Data.Prices, uc.Config.RecommendWeights, uc.onTimeRates(ctx, resp.Data.Prices))
+	sortPrices(resp.Data.Prices, req.SortBy)
+	resp.Meta = model.RateMeta{Cache: cacheStatus}
+
+	if req.SkipQuote {
+		return model.Success(), resp
+	}
+
+	quoteInput := QuoteInput{
+		Origin:      model.QuoteLocation{AreaID: bsReq.OriginAreaID, PostalCode: resp.Data.Origin.PostalCode},
+		Destination: model.QuoteLocation{AreaID: bsReq.DestinationAreaID, PostalCode: resp.Data.Destination.PostalCode},
+		Items:       req.Items,
+	}
+	if err := uc.QuoteUC.CreateQuotes(ctx, quoteInput, resp.Data.Prices); err != nil {
+		return model.DefaultError("Failed to save quotes", nil), nil
+	}
+
+	return model.Success(), resp
+}
+
+// onTimeRates loads the historical on-time rate of the quoted services, falling back to the configured default
+func (uc *ShippingUseCase) onTimeRates(ctx context.Context, prices []model.CourierPrice) func(model.CourierPrice) float64 {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	courierCodes := make([]string, 0, len(prices))
+	for _, price := range prices {
+		courierCodes = append(courierCodes, price.CourierCode)
+	}
+
+	rates := make(map[string]float64)
+	services, err := uc.ServiceRepo.FindByCourierCodes(uc.DB, courierCodes)
+	if err != nil {
+		log.Errorf("Error loading courier services: %v", err)
+	}
+	for _, service := range services {
+		if service.OnTimeRate > 0 {
+			rates[service.CourierCode+"::"+service.ServiceCode] = service.OnTimeRate
+		}
+	}
+
+	return func(price model.CourierPrice) float64 {
+		if rate, ok := rates[price.CourierCode+"::"+price.ServiceCode]; ok {
+			return rate
+		}
+		return uc.Config.DefaultOnTimeRate
+	}
+}
+
+// courierGroup is a set of couriers quoted with a single provider call
+type courierGroup struct {
+	divisor  int  // Divisor used for chargeable weight, 0 to send actual weight
+	instant  bool // Couriers are instant couriers quoted with coordinates
+	couriers []string
+}
+
+// groupCouriers splits the comma separated courier list into groups that can share a provider call.
+// All couriers share one group unless chargeable weight is sent upstream or instant couriers are quoted
+// with coordinates. Duplicate couriers are dropped and groups are returned in a stable order.
+func (uc *ShippingUseCase) groupCouriers(courierCodes string, hasCoordinates bool) []courierGroup {
+	sendChargeableWeight := uc.Settings.Load().SendChargeableWeight
+	groups := make([]courierGroup, 0)
+	seen := make(map[string]bool)
+	for _, code := range strings.Split(courierCodes, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[strings.ToLower(code)] {
+			continue
+		}
+		seen[strings.ToLower(code)] = true
+
+		key := courierGroup{instant: hasCoordinates && uc.Config.IsInstantCourier(code)}
+		if sendChargeableWeight {
+			key.divisor = uc.Config.DivisorFor(code)
+		}
+
+		index := slices.IndexFunc(groups, func(group courierGroup) bool {
+			return group.divisor == key.divisor && group.instant == key.instant
+		})
+		if index < 0 {
+			groups = append(groups, key)
+			index = len(groups) - 1
+		}
+		groups[index].couriers = append(groups[index].couriers, code)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].instant != groups[j].instant {
+			return !groups[i].instant
+		}
+		return groups[i].divisor < groups[j].divisor
+	})
+	return groups
+}
+
+// toBiteshipItems converts the requested items, replacing each item weight with its chargeable weight
+// when a divisor is given
+func (uc *ShippingUseCase) toBiteshipItems(items []model.ItemRequest, divisor int) []biteship.Item {
+	bsItems := make([]biteship.Item, 0, len(items))
+	for _, item := range items {
+		weight := item.Weight
+		if divisor > 0 {
+			weight = item.ChargeableWeight(divisor)
+		}
+		bsItems = append(bsItems, biteship.Item{
 			Name:        item.Name,
 			Description: item.Description,
 			Value:       item.Price,
-			Weight:      item.Weight,
+			Weight:      weight,
 			Length:      item.Length,
 			Width:       item.Width,
 			Height:      item.Height,
 			Quantity:    item.Quantity,
 		})
 	}
+	return bsItems
+}
+
+// fetchCourierRates returns the rates for a provider request from the cache of the tenant, or from the provider
+// while sharing one in-flight call between concurrent identical requests. The returned response is a copy
+// owned by the caller.
+func (uc *ShippingUseCase) fetchCourierRates(ctx context.Context, bc *biteship.Client, bsReq biteship.RateRequest, bypassCache bool) (*model.CourierRateResponse, model.RateCacheStatus, *biteship.ErrorResponse) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	// merchants may have negotiated their own rates, so each tenant has its own cache
+	rdsKey := TenantKey(ctx, rateCacheKey(bsReq))
+	cacheStatus := model.RateCacheBypass
+	if !bypassCache {
+		cacheStatus = model.RateCacheMiss
+		cachedData, err := uc.Redis.Get(ctx, rdsKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			log.Errorf("Error getting cached rates: %v", err)
+		}
+
+		if cachedData != "" {
+			var resp model.CourierRateResponse
+			if err := json.Unmarshal([]byte(cachedData), &resp); err != nil {
+				log.Errorf("Error unmarshalling cached rates: %v", err)
+			} else {
+				log.Debugf("Cache hit for rates with key: %s", rdsKey)
+				return &resp, model.RateCacheHit, nil
+			}
+		}
+	}
+
+	v, _, shared := uc.rateGroup.Do(rdsKey, func() (any, error) {
+		// callers share this call and its cached result, so one of them going away must not cancel it for the others
+		sharedCtx := context.WithoutCancel(ctx)
+		rateResponse, errResp := bc.GetCourierRates(sharedCtx, bsReq)
+		if errResp != nil {
+			return rateResult{errResp: errResp}, nil
+		}
+
+		resp := rateResponse.ToCourierRateResponse()
+		if bCache, err := json.Marshal(resp); err != nil {
+			log.Errorf("Error marshalling rates for cache: %v", err)
+		} else if err = uc.Redis.Set(sharedCtx, rdsKey, bCache, uc.Settings.Load().RateCacheTTL).Err(); err != nil {
+			log.Errorf("Error setting rates cache: %v", err)
+		}
+
+		return rateResult{resp: &resp}, nil
+	})
+	if shared {
+		log.Debugf("Shared in-flight provider call for rates with key: %s", rdsKey)
+	}
+
+	result := v.(rateResult)
+	if result.errResp != nil {
+		return nil, cacheStatus, result.errResp
+	}
 
-	rateResponse, errResp := uc.BiteshipClient.GetCourierRates(bsReq)
-	if errResp != nil {
-		if errResp.IsEmptyData() {
-			log.Infof("No courier rates found for request: %+v", req)
-			return model.NotFound("No courier rates found"), nil
+	// copy the shared response so callers never mutate each other's data
+	resp := *result.resp
+	resp.Data.Prices = append([]model.CourierPrice(nil), result.resp.Data.Prices...)
+	return &resp, cacheStatus, nil
+}
+
+// rateCacheKey builds a cache key from the resolved locations and coordinates, courier list and item metrics and values,
+// so requests that differ only in ordering or item names share the same cached rates.
+func rateCacheKey(req biteship.RateRequest) string {
+	couriers := make([]string, 0)
+	for _, code := range strings.Split(req.Couriers, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code != "" {
+			couriers = append(couriers, code)
 		}
-		log.Errorf("Error getting courier rates from Biteship: %v", errResp)
-		return model.DefaultError("Failed to get courier rates", nil), nil
 	}
+	sort.Strings(couriers)
+
+	items := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, fmt.Sprintf("%d:%d:%d:%d:%d:%d", item.Weight, item.Length, item.Width, item.Height, item.Quantity, item.Value))
+	}
+	sort.Strings(items)
+
+	normalized := fmt.Sprintf("%s|%d|%.5f,%.5f|%s|%d|%.5f,%.5f|%s|%s",
+		req.OriginAreaID, req.OriginPostalCode, req.OriginLatitude, req.OriginLongitude,
+		req.DestinationAreaID, req.DestinationPostalCode, req.DestinationLatitude, req.DestinationLongitude,
+		strings.Join(couriers, ","), strings.Join(items, ";"))
 
-	resp := rateResponse.ToCourierRateResponse()
-	return model.Success(), &resp
+	hash := sha256.Sum256([]byte(normalized))
+	return fmt.Sprintf("rates::%s", hex.EncodeToString(hash[:]))
 }
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/model"
+	"strings"
+	"testing"
+)
+
+func testRateRequest() biteship.RateRequest {
+	return biteship.RateRequest{
+		OriginAreaID:      "IDNP6IDNC148IDND836IDZ12410",
+		DestinationAreaID: "IDNP9IDNC87IDND4560IDZ40132",
+		Couriers:          "jne,sicepat",
+		Items: []biteship.Item{
+			{Name: "Shoes", Value: 250000, Weight: 800, Length: 30, Width: 20, Height: 12, Quantity: 1},
+			{Name: "Socks", Value: 30000, Weight: 100, Length: 10, Width: 10, Height: 5, Quantity: 3},
+		},
+	}
+}
+
+func TestRateCacheKey(t *testing.T) {
+	base := rateCacheKey(testRateRequest())
+	if !strings.HasPrefix(base, "rates::") {
+		t.Fatalf("rateCacheKey() = %q, want a rates:: key", base)
+	}
+
+	tests := []struct {
+		name   string
+		change func(req *biteship.RateRequest)
+		same   bool
+	}{
+		{"courier order and case", func(req *biteship.RateRequest) { req.Couriers = " SICEPAT , jne," }, true},
+		{"item order", func(req *biteship.RateRequest) { req.Items[0], req.Items[1] = req.Items[1], req.Items[0] }, true},
+		{"item names", func(req *biteship.RateRequest) { req.Items[0].Name, req.Items[0].Description = "Sneakers", "red" }, true},
+		{"courier list", func(req *biteship.RateRequest) { req.Couriers = "jne" }, false},
+		{"destination", func(req *biteship.RateRequest) { req.DestinationAreaID = "IDNP9IDNC87IDND4561IDZ40135" }, false},
+		{"postal code", func(req *biteship.RateRequest) { req.OriginPostalCode = 12410 }, false},
+		{"coordinates", func(req *biteship.RateRequest) { req.OriginLatitude, req.OriginLongitude = -6.2, 106.8 }, false},
+		{"item weight", func(req *biteship.RateRequest) { req.Items[1].Weight = 150 }, false},
+		{"item quantity", func(req *biteship.RateRequest) { req.Items[1].Quantity = 4 }, false},
+		{"item value", func(req *biteship.RateRequest) { req.Items[0].Value = 260000 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testRateRequest()
+			tt.change(&req)
+			if got := rateCacheKey(req); (got == base) != tt.same {
+				t.Errorf("rateCacheKey() = %q, base %q, want same key %v", got, base, tt.same)
+			}
+		})
+	}
+}
+
+func TestRateCacheKeyTenantScoped(t *testing.T) {
+	key := rateCacheKey(testRateRequest())
+
+	platform := TenantKey(context.Background(), key)
+	merchantA := TenantKey(WithTenant(context.Background(), "merchant-a"), key)
+	merchantB := TenantKey(WithTenant(context.Background(), "merchant-b"), key)
+
+	if platform != key {
+		t.Errorf("platform key = %q, want %q", platform, key)
+	}
+	if merchantA != "tenant::merchant-a::"+key {
+		t.Errorf("merchant key = %q, want it scoped to the merchant", merchantA)
+	}
+	if merchantA == merchantB || merchantA == platform {
+		t.Errorf("keys of different tenants must differ: %q, %q, %q", platform, merchantA, merchantB)
+	}
+}
+
+func TestShippingConfigDivisorFor(t *testing.T) {
+	config := ShippingConfig{VolumetricDivisor: 6000, CourierDivisors: map[string]int{"jne": 5000, "tiki": 0}}
+
+	tests := []struct {
+		courier string
+		want    int
+	}{
+		{"jne", 5000},
+		{"JNE", 5000},
+		{"tiki", 6000},
+		{"sicepat", 6000},
+	}
+	for _, tt := range tests {
+		if got := config.DivisorFor(tt.courier); got != tt.want {
+			t.Errorf("DivisorFor(%q) = %d, want %d", tt.courier, got, tt.want)
+		}
+	}
+}
+
+func TestGroupCouriers(t *testing.T) {
+	config := ShippingConfig{VolumetricDivisor: 6000, CourierDivisors: map[string]int{"jne": 5000}, InstantCouriers: []string{"gojek"}}
+
+	tests := []struct {
+		name                 string
+		sendChargeableWeight bool
+		hasCoordinates       bool
+		want                 []courierGroup
+	}{
+		{
+			name: "one call for actual weight",
+			want: []courierGroup{{couriers: []string{"sicepat", "jne", "gojek"}}},
+		},
+		{
+			name:                 "one call per divisor for chargeable weight",
+			sendChargeableWeight: true,
+			want:                 []courierGroup{{divisor: 5000, couriers: []string{"jne"}}, {divisor: 6000, couriers: []string{"sicepat", "gojek"}}},
+		},
+		{
+			name:           "instant couriers quoted last with coordinates",
+			hasCoordinates: true,
+			want:           []courierGroup{{couriers: []string{"sicepat", "jne"}}, {instant: true, couriers: []string{"gojek"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &ShippingUseCase{Config: config, Settings: NewSettings(RuntimeSettings{SendChargeableWeight: tt.sendChargeableWeight})}
+			got := uc.groupCouriers("sicepat, jne,,gojek,SICEPAT", tt.hasCoordinates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("groupCouriers() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].divisor != tt.want[i].divisor || got[i].instant != tt.want[i].instant ||
+					strings.Join(got[i].couriers, ",") != strings.Join(tt.want[i].couriers, ",") {
+					t.Fatalf("groupCouriers() = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestToBiteshipItems(t *testing.T) {
+	items := []model.ItemRequest{{Name: "Pillow", Price: 90000, Metrics: model.Metrics{Length: 60, Width: 40, Height: 30, Weight: 1500}, Quantity: 2}}
+	uc := &ShippingUseCase{}
+
+	if got := uc.toBiteshipItems(items, 0)[0]; got.Weight != 1500 || got.Value != 90000 || got.Quantity != 2 {
+		t.Errorf("toBiteshipItems() without divisor = %+v, want the actual weight", got)
+	}
+	if got := uc.toBiteshipItems(items, 6000)[0]; got.Weight != 12000 {
+		t.Errorf("toBiteshipItems() with divisor = %+v, want the chargeable weight", got)
+	}
+}
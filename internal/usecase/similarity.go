@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// regionPrefixes are administrative type words that do not help telling regions apart
+var regionPrefixes = map[string]bool{
+	"provinsi": true, "prov": true,
+	"kabupaten": true, "kab": true, "kota": true, "kodya": true,
+	"kecamatan": true, "kec": true,
+	"kelurahan": true, "kel": true, "desa": true,
+}
+
+// normalizeRegionName lowercases a region name, replaces punctuation with spaces and drops administrative type words
+func normalizeRegionName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, name)
+
+	words := make([]string, 0)
+	for _, word := range strings.Fields(cleaned) {
+		if !regionPrefixes[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// nameSimilarity returns how alike two region names are, from 0 for unrelated to 1 for equal after normalization
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeRegionName(a), normalizeRegionName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	longest := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"shipping-gateway/external/biteship"
+	"shipping-gateway/internal/entity"
+	"shipping-gateway/internal/model"
+	"shipping-gateway/internal/repository"
+	"shipping-gateway/internal/secret"
+	"sync"
+	"time"
+)
+
+// ErrMerchantDisabled is returned when resolving the tenant of a disabled merchant
+var ErrMerchantDisabled = errors.New("merchant is disabled")
+
+// TenantID returns the ID of the merchant a request is made for, empty when it is made for the platform itself
+func TenantID(ctx context.Context) string {
+	id, _ := ctx.Value("tenantId").(string)
+	return id
+}
+
+// WithTenant returns a context for requests made for a merchant, for work started outside of an HTTP request
+func WithTenant(ctx context.Context, merchantID string) context.Context {
+	return context.WithValue(ctx, "tenantId", merchantID)
+}
+
+// TenantKey scopes a Redis key to the tenant of the request. Platform keys are left unchanged.
+func TenantKey(ctx context.Context, key string) string {
+	if id := TenantID(ctx); id != "" {
+		return fmt.Sprintf("tenant::%s::%s", id, key)
+	}
+	return key
+}
+
+// Tenant is the merchant a request is made for with the provider client of its own account
+type Tenant struct {
+	Merchant *entity.Merchant // nil for the platform
+	Biteship *biteship.Client
+}
+
+type pooledTenant struct {
+	tenant   *Tenant
+	loadedAt time.Time
+}
+
+// TenantRegistry builds the provider clients of merchants from their encrypted credentials and keeps them pooled,
+// so credentials are only decrypted when a merchant is first seen, changed, or its entry expired
+type TenantRegistry struct {
+	DB           *gorm.DB
+	Log          *logrus.Logger
+	MerchantRepo *repository.MerchantRepository
+	Cipher       *secret.Cipher
+	Platform     *Tenant       // Tenant of requests that are not made for a merchant
+	TTL          time.Duration // How long a pooled tenant is used before it is loaded again
+
+	mu      sync.RWMutex
+	tenants map[string]pooledTenant
+}
+
+func NewTenantRegistry(db *gorm.DB, log *logrus.Logger, merchantRepo *repository.MerchantRepository, cipher *secret.Cipher,
+	bc *biteship.Client, ttl time.Duration) *TenantRegistry {
+	return &TenantRegistry{
+		DB:           db,
+		Log:          log,
+		MerchantRepo: merchantRepo,
+		Cipher:       cipher,
+		Platform:     &Tenant{Biteship: bc},
+		TTL:          ttl,
+		tenants:      make(map[string]pooledTenant),
+	}
+}
+
+// Resolve returns the tenant of the request
+func (r *TenantRegistry) Resolve(ctx context.Context) (*Tenant, error) {
+	merchantID := TenantID(ctx)
+	if merchantID == "" {
+		return r.Platform, nil
+	}
+
+	r.mu.RLock()
+	pooled, ok := r.tenants[merchantID]
+	r.mu.RUnlock()
+	if ok && time.Since(pooled.loadedAt) < r.TTL {
+		return pooled.tenant, r.checkEnabled(pooled.tenant)
+	}
+
+	merchant, err := r.MerchantRepo.FindByID(r.DB, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading merchant %s: %w", merchantID, err)
+	}
+	credentials, err := r.Credentials(merchant)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := &Tenant{Merchant: merchant, Biteship: r.Platform.Biteship.WithAPIKey(credentials.BiteshipAPIKey)}
+	r.mu.Lock()
+	r.tenants[merchantID] = pooledTenant{tenant: tenant, loadedAt: time.Now()}
+	r.mu.Unlock()
+
+	r.Log.WithField("traceId", ctx.Value("traceId")).Debugf("Loaded provider clients of merchant %s", merchantID)
+	return tenant, r.checkEnabled(tenant)
+}
+
+// Credentials decrypts the provider credentials of a merchant
+func (r *TenantRegistry) Credentials(merchant *entity.Merchant) (model.ProviderCredentials, error) {
+	var credentials model.ProviderCredentials
+	plaintext, err := r.Cipher.Decrypt(merchant.Credentials)
+	if err != nil {
+		return credentials, fmt.Errorf("error decrypting credentials of merchant %s: %w", merchant.ID, err)
+	}
+	if err = json.Unmarshal(plaintext, &credentials); err != nil {
+		return credentials, fmt.Errorf("error parsing credentials of merchant %s: %w", merchant.ID, err)
+	}
+	return credentials, nil
+}
+
+// Invalidate drops the pooled tenant of a merchant so its next request picks up changed credentials or settings
+func (r *TenantRegistry) Invalidate(merchantID string) {
+	r.mu.Lock()
+	delete(r.tenants, merchantID)
+	r.mu.Unlock()
+}
+
+func (r *TenantRegistry) checkEnabled(tenant *Tenant) error {
+	if tenant.Merchant != nil && !tenant.Merchant.Enabled {
+		return ErrMerchantDisabled
+	}
+	return nil
+}
+
+// tenantErrorResponse converts an error returned by Resolve to a service response
+func tenantErrorResponse(log *logrus.Entry, err error) *model.ServiceResponse {
+	if errors.Is(err, ErrMerchantDisabled) {
+		return model.Forbidden("Merchant is disabled")
+	}
+	log.Errorf("Error resolving tenant: %v", err)
+	return model.DefaultError("Failed to load merchant", nil)
+}
@@ -8,28 +8,38 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
-	"shipping-gateway/external/biteship"
+	"net/http"
 	"shipping-gateway/internal/model"
 	"shipping-gateway/internal/model/converter"
 	"shipping-gateway/internal/repository"
+	"strings"
 	"time"
 )
 
 type TrackingUseCase struct {
 	DB              *gorm.DB
 	Log             *logrus.Logger
-	BiteshipClient  *biteship.Client
+	Tenants         *TenantRegistry
 	Redis           *redis.Client
 	TrackingLogRepo *repository.TrackingLogRepository
+	WaybillRegistry *WaybillRegistry
+	Couriers        *CourierRegistry
+	MaxAttempts     int // Maximum number of candidate couriers tried when detecting the courier of a waybill
+	Settings        *Settings
 }
 
-func NewTrackingUseCase(db *gorm.DB, log *logrus.Logger, biteshipClient *biteship.Client, redis *redis.Client, trackingLogRepo *repository.TrackingLogRepository) *TrackingUseCase {
+func NewTrackingUseCase(db *gorm.DB, log *logrus.Logger, tenants *TenantRegistry, redis *redis.Client, trackingLogRepo *repository.TrackingLogRepository,
+	waybillRegistry *WaybillRegistry, couriers *CourierRegistry, maxAttempts int, settings *Settings) *TrackingUseCase {
 	return &TrackingUseCase{
 		DB:              db,
 		Log:             log,
-		BiteshipClient:  biteshipClient,
+		Tenants:         tenants,
 		Redis:           redis,
 		TrackingLogRepo: trackingLogRepo,
+		WaybillRegistry: waybillRegistry,
+		Couriers:        couriers,
+		MaxAttempts:     maxAttempts,
+		Settings:        settings,
 	}
 }
 
@@ -40,12 +50,28 @@ func (uc *TrackingUseCase) GetTrackingByWaybill(ctx context.Context, waybill, co
 
 	// batas waktu harus refetch data dari biteship
 	// misal 1 jam, jika sudah lebih dari 1 jam, maka harus refetch data dari biteship
-	refetchDuration := 2 * time.Hour
+	refetchDuration := uc.Settings.Load().TrackingRefetchInterval
+	// The same parcel must share one cache key and one tracking log whatever spelling of the courier is used
+	courier = uc.Couriers.Canonical(courier)
 	if waybill == "" || courier == "" {
 		return model.BadRequest("Waybill and Courier are required", nil), nil
 	}
 
-	rdsKey := fmt.Sprintf("tracking::%s::%s", waybill, courier)
+	// A waybill in the wrong format would only come back as not found from Biteship
+	if !uc.WaybillRegistry.Validate(courier, waybill) {
+		message := fmt.Sprintf("Waybill %s does not match the format of courier %s", waybill, courier)
+		candidates := uc.WaybillRegistry.Detect(waybill)
+		if len(candidates) > 0 {
+			codes := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				codes = append(codes, candidate.CourierCode)
+			}
+			message += fmt.Sprintf(", it matches %s", strings.Join(codes, ", "))
+		}
+		return model.BadRequest(message, candidates), nil
+	}
+
+	rdsKey := TenantKey(ctx, fmt.Sprintf("tracking::%s::%s", waybill, courier))
 	cachedData, err := uc.Redis.Get(ctx, rdsKey).Result()
 	if err != nil && !errors.Is(err, redis.Nil) {
 		log.Errorf("Error getting cached data: %v", err)
@@ -64,7 +90,7 @@ func (uc *TrackingUseCase) GetTrackingByWaybill(ctx context.Context, waybill, co
 	}
 
 	// If cache miss or data is stale, fetch from database first
-	trackingLog, err := uc.TrackingLogRepo.FindByWaybillAndCourierCode(uc.DB, waybill, courier)
+	trackingLog, err := uc.TrackingLogRepo.FindByWaybillAndCourierCode(uc.DB, TenantID(ctx), waybill, courier)
 	if err == nil && trackingLog != nil {
 		log.Debugf("Found tracking log in DB for waybill: %s, courier: %s", waybill, courier)
 		// convert tracking log to response data
@@ -95,7 +121,11 @@ func (uc *TrackingUseCase) GetTrackingByWaybill(ctx context.Context, waybill, co
 		log.Errorf("Error finding tracking log: %v", err)
 	}
 
-	biteshipResp, biteshipErr := uc.BiteshipClient.GetTrackingByWaybill(waybill, courier)
+	tenant, err := uc.Tenants.Resolve(ctx)
+	if err != nil {
+		return tenantErrorResponse(log, err), nil
+	}
+	biteshipResp, biteshipErr := tenant.Biteship.GetTrackingByWaybill(ctx, waybill, uc.Couriers.ProviderCode(ProviderBiteship, courier))
 	if biteshipErr != nil {
 		return biteshipErr.ToServiceResponse(), nil
 	}
@@ -105,11 +135,17 @@ func (uc *TrackingUseCase) GetTrackingByWaybill(ctx context.Context, waybill, co
 		log.Errorf("Error converting Biteship response to ShipmentTrackingResponse: %v", err)
 		return model.NotFound("Tracking not found"), nil
 	}
+	if providerCourier := uc.Couriers.FromProvider(ProviderBiteship, responseData.CourierCode); providerCourier != courier {
+		log.Warnf("Biteship reported courier %s for waybill %s tracked with courier %s, add an alias if they are the same",
+			providerCourier, waybill, courier)
+	}
+	responseData.CourierCode = courier
 
 	trackingLogData, errEntity := converter.ShipmentTrackingLogResponseToEntity(responseData)
 	if errEntity != nil {
 		log.Errorf("Error converting response data to entity: %v", errEntity)
 	} else {
+		trackingLogData.MerchantID = TenantID(ctx)
 		errSave := uc.TrackingLogRepo.CreateOrUpdate(uc.DB, trackingLogData)
 		if errSave != nil {
 			log.Warnf("Error saving tracking log: %v", errSave)
@@ -127,3 +163,39 @@ func (uc *TrackingUseCase) GetTrackingByWaybill(ctx context.Context, waybill, co
 
 	return model.Success(), responseData
 }
+
+// DetectTracking finds the courier of a waybill by trying the couriers whose waybill format matches it, most
+// likely first, and returns the first tracking found with the candidates considered
+func (uc *TrackingUseCase) DetectTracking(ctx context.Context, waybill string) (*model.ServiceResponse, *model.ShipmentTrackingResponse, []model.CourierCandidate) {
+	log := uc.Log.WithField("traceId", ctx.Value("traceId"))
+
+	candidates := uc.WaybillRegistry.Detect(waybill)
+	if len(candidates) == 0 {
+		return model.UnprocessableEntity(fmt.Sprintf("Waybill %s does not match the format of any known courier", waybill)), nil, nil
+	}
+
+	tried := candidates
+	if uc.MaxAttempts > 0 && len(tried) > uc.MaxAttempts {
+		tried = tried[:uc.MaxAttempts]
+	}
+
+	var lastErr *model.ServiceResponse
+	for _, candidate := range tried {
+		ucResp, resp := uc.GetTrackingByWaybill(ctx, waybill, candidate.CourierCode)
+		if ucResp.StatusCode == http.StatusOK {
+			log.Infof("Detected courier %s for waybill %s", candidate.CourierCode, waybill)
+			return ucResp, resp, candidates
+		}
+
+		log.Infof("Waybill %s not tracked with courier %s: %s", waybill, candidate.CourierCode, ucResp.Message)
+		if ucResp.StatusCode != http.StatusNotFound {
+			lastErr = ucResp
+		}
+	}
+
+	// a provider failure says more than not found, the waybill may still belong to that courier
+	if lastErr != nil {
+		return lastErr, nil, candidates
+	}
+	return model.NotFound(fmt.Sprintf("Tracking not found for waybill %s with any of the matching couriers", waybill)), nil, candidates
+}
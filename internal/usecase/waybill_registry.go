@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"shipping-gateway/internal/model"
+	"sort"
+	"strings"
+)
+
+// WaybillPattern describes one waybill format of a courier
+type WaybillPattern struct {
+	Pattern    string  `mapstructure:"pattern"`    // Regular expression matched against the upper-cased waybill
+	Likelihood float64 `mapstructure:"likelihood"` // How likely a matching waybill belongs to the courier, from 0 to 1
+}
+
+type compiledWaybillPattern struct {
+	regexp     *regexp.Regexp
+	likelihood float64
+}
+
+// WaybillRegistry knows the waybill formats of each courier. Couriers without patterns accept any waybill.
+type WaybillRegistry struct {
+	patterns map[string][]compiledWaybillPattern
+}
+
+func NewWaybillRegistry(patterns map[string][]WaybillPattern) (*WaybillRegistry, error) {
+	registry := &WaybillRegistry{patterns: make(map[string][]compiledWaybillPattern, len(patterns))}
+	for courier, courierPatterns := range patterns {
+		courier = strings.ToLower(courier)
+		for _, pattern := range courierPatterns {
+			re, err := regexp.Compile(pattern.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid waybill pattern %q of courier %s: %w", pattern.Pattern, courier, err)
+			}
+			registry.patterns[courier] = append(registry.patterns[courier], compiledWaybillPattern{regexp: re, likelihood: pattern.Likelihood})
+		}
+	}
+	return registry, nil
+}
+
+// Validate reports whether a waybill has a valid format for the courier. Couriers without patterns are not
+// checked.
+func (r *WaybillRegistry) Validate(courier, waybill string) bool {
+	patterns, ok := r.patterns[strings.ToLower(courier)]
+	if !ok {
+		return true
+	}
+	return r.match(patterns, waybill) > 0
+}
+
+// Detect returns the couriers whose formats match a waybill, most likely first
+func (r *WaybillRegistry) Detect(waybill string) []model.CourierCandidate {
+	candidates := make([]model.CourierCandidate, 0)
+	for courier, patterns := range r.patterns {
+		if likelihood := r.match(patterns, waybill); likelihood > 0 {
+			candidates = append(candidates, model.CourierCandidate{CourierCode: courier, Likelihood: likelihood})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Likelihood != candidates[j].Likelihood {
+			return candidates[i].Likelihood > candidates[j].Likelihood
+		}
+		return candidates[i].CourierCode < candidates[j].CourierCode
+	})
+	return candidates
+}
+
+// match returns the highest likelihood of the patterns matching the waybill, 0 when none match
+func (r *WaybillRegistry) match(patterns []compiledWaybillPattern, waybill string) float64 {
+	waybill = strings.ToUpper(strings.TrimSpace(waybill))
+	best := 0.0
+	for _, pattern := range patterns {
+		if pattern.regexp.MatchString(waybill) {
+			// a pattern without likelihood still counts as a match
+			best = max(best, pattern.likelihood, 0.01)
+		}
+	}
+	return best
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"reflect"
+	"shipping-gateway/internal/model"
+	"testing"
+)
+
+func newTestWaybillRegistry(t *testing.T) *WaybillRegistry {
+	t.Helper()
+	registry, err := NewWaybillRegistry(map[string][]WaybillPattern{
+		"JNE": {
+			{Pattern: "^[A-Z]{3}[A-Z0-9]{10,13}$", Likelihood: 0.7},
+			{Pattern: "^[0-9]{15,16}$", Likelihood: 0.5},
+		},
+		"jnt":      {{Pattern: "^J[PDX][0-9]{10}$", Likelihood: 0.95}},
+		"sicepat":  {{Pattern: "^00[0-9]{10}$", Likelihood: 0.8}},
+		"pos":      {{Pattern: "^[0-9]{15}$", Likelihood: 0.5}},
+		"lion":     {{Pattern: "^[0-9]{12}$"}},
+		"anteraja": {{Pattern: "^1[0-9]{13}$", Likelihood: 0.7}},
+	})
+	if err != nil {
+		t.Fatalf("NewWaybillRegistry() error = %v", err)
+	}
+	return registry
+}
+
+func TestWaybillRegistryDetect(t *testing.T) {
+	registry := newTestWaybillRegistry(t)
+
+	tests := []struct {
+		name    string
+		waybill string
+		want    []model.CourierCandidate
+	}{
+		{"single courier", "JP1234567890", []model.CourierCandidate{{CourierCode: "jnt", Likelihood: 0.95}}},
+		{"lower-cased and padded", "  jp1234567890 ", []model.CourierCandidate{{CourierCode: "jnt", Likelihood: 0.95}}},
+		{
+			name:    "most likely first, pattern without likelihood last",
+			waybill: "001234567890",
+			want:    []model.CourierCandidate{{CourierCode: "sicepat", Likelihood: 0.8}, {CourierCode: "lion", Likelihood: 0.01}},
+		},
+		{
+			name:    "ties ordered by courier code",
+			waybill: "123456789012345",
+			want:    []model.CourierCandidate{{CourierCode: "jne", Likelihood: 0.5}, {CourierCode: "pos", Likelihood: 0.5}},
+		},
+		{"best pattern of a courier counts", "CGK1234567890", []model.CourierCandidate{{CourierCode: "jne", Likelihood: 0.7}}},
+		{"no match", "XYZ", []model.CourierCandidate{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// map iteration is random, so repeat to catch ordering that depends on it
+			for i := 0; i < 20; i++ {
+				if got := registry.Detect(tt.waybill); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("Detect(%q) = %+v, want %+v", tt.waybill, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWaybillRegistryValidate(t *testing.T) {
+	registry := newTestWaybillRegistry(t)
+
+	tests := []struct {
+		courier string
+		waybill string
+		want    bool
+	}{
+		{"jnt", "JP1234567890", true},
+		{"JNT", "jp1234567890", true},
+		{"jnt", "001234567890", false},
+		{"jne", "123456789012345", true},
+		{"gojek", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := registry.Validate(tt.courier, tt.waybill); got != tt.want {
+			t.Errorf("Validate(%q, %q) = %v, want %v", tt.courier, tt.waybill, got, tt.want)
+		}
+	}
+}
+
+func TestNewWaybillRegistryInvalidPattern(t *testing.T) {
+	if _, err := NewWaybillRegistry(map[string][]WaybillPattern{"jne": {{Pattern: "^[A-Z"}}}); err == nil {
+		t.Error("NewWaybillRegistry() error = nil, want the invalid pattern reported")
+	}
+}